@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// repoAndRunID reads the "repo" and "run_id" arguments shared by the tools
+// that act on a single workflow run.
+func repoAndRunID(req mcp.CallToolRequest) (owner string, repo string, runID int64, err error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return "", "", 0, fmt.Errorf("missing required argument: repo")
+	}
+
+	owner, repo, err = parseRepo(repoArg)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	runID = int64(req.GetInt("run_id", 0))
+	if runID <= 0 {
+		return "", "", 0, fmt.Errorf("missing or invalid run_id")
+	}
+
+	return owner, repo, runID, nil
+}
+
+const (
+	defaultLogLines = 100
+	maxLogLines     = 1000
+)
+
+func (s *githubService) listWorkflowRunsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	limit := getLimit(req, 20)
+	opts := &github.ListWorkflowRunsOptions{
+		Branch:      req.GetString("branch", ""),
+		Status:      req.GetString("status", ""),
+		Event:       req.GetString("event", ""),
+		ListOptions: github.ListOptions{PerPage: limit},
+	}
+
+	// The API returns runs newest first.
+	var runs []*github.WorkflowRun
+	total := 0
+	for {
+		result, resp, err := s.restClient.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, opts)
+		if err != nil {
+			return apiError("Failed to list workflow runs", err)
+		}
+
+		total = result.GetTotalCount()
+		runs = append(runs, result.WorkflowRuns...)
+
+		if len(runs) >= limit || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+
+	if total == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No workflow runs found in %s/%s.", owner, repo)), nil
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %d workflow runs in %s/%s, showing %d:\n\n", total, owner, repo, len(runs)))
+	for _, run := range runs {
+		conclusion := run.GetConclusion()
+		if conclusion == "" {
+			conclusion = "-"
+		}
+		responseBuilder.WriteString(fmt.Sprintf("- %s #%d [%s / %s] on %s (%s, run ID %d)\n  %s\n",
+			run.GetName(),
+			run.GetRunNumber(),
+			run.GetStatus(),
+			conclusion,
+			run.GetHeadBranch(),
+			run.GetEvent(),
+			run.GetID(),
+			run.GetHTMLURL(),
+		))
+	}
+
+	responseBuilder.WriteString(paginationFooter(len(runs), total))
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func (s *githubService) rerunFailedJobsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	owner, repo, runID, err := repoAndRunID(req)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	run, _, err := s.restClient.Actions.GetWorkflowRunByID(ctx, owner, repo, runID)
+	if err != nil {
+		return apiError("Failed to get workflow run", err)
+	}
+
+	if run.GetStatus() != "completed" {
+		return toolError(fmt.Sprintf("Workflow run %d is still %s; only completed runs can be re-run.\n%s", runID, run.GetStatus(), run.GetHTMLURL())), nil
+	}
+
+	if _, err := s.restClient.Actions.RerunFailedJobsByID(ctx, owner, repo, runID); err != nil {
+		return apiError(fmt.Sprintf("Workflow run %d cannot be re-run", runID), err)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Re-running failed jobs of %s #%d.\n%s", run.GetName(), run.GetRunNumber(), run.GetHTMLURL())), nil
+}
+
+func (s *githubService) cancelWorkflowRunHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	owner, repo, runID, err := repoAndRunID(req)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	run, _, err := s.restClient.Actions.GetWorkflowRunByID(ctx, owner, repo, runID)
+	if err != nil {
+		return apiError("Failed to get workflow run", err)
+	}
+
+	if run.GetStatus() == "completed" {
+		return toolError(fmt.Sprintf("Workflow run %d has already completed (%s); there is nothing to cancel.\n%s", runID, run.GetConclusion(), run.GetHTMLURL())), nil
+	}
+
+	// GitHub accepts the cancellation with 202 and stops the run asynchronously.
+	if _, err := s.restClient.Actions.CancelWorkflowRunByID(ctx, owner, repo, runID); err != nil {
+		var accepted *github.AcceptedError
+		if !errors.As(err, &accepted) {
+			return apiError(fmt.Sprintf("Workflow run %d cannot be cancelled", runID), err)
+		}
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Cancelling %s #%d.\n%s", run.GetName(), run.GetRunNumber(), run.GetHTMLURL())), nil
+}
+
+// getCheckRunLogsHandler returns the end of a GitHub Actions job's log, where
+// the error that failed the job usually is. A check run created by Actions
+// shares its ID with the job, so either ID works.
+func (s *githubService) getCheckRunLogsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	jobID := int64(req.GetInt("check_run_id", 0))
+	if jobID <= 0 {
+		jobID = int64(req.GetInt("job_id", 0))
+	}
+	if jobID <= 0 {
+		return toolError("Missing required argument: check_run_id or job_id"), nil
+	}
+
+	lines := req.GetInt("lines", defaultLogLines)
+	if lines < 1 {
+		lines = defaultLogLines
+	}
+	if lines > maxLogLines {
+		lines = maxLogLines
+	}
+
+	logURL, resp, err := s.restClient.Actions.GetWorkflowJobLogs(ctx, owner, repo, jobID, 1)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusGone || resp.StatusCode == http.StatusNotFound) {
+			return toolError(fmt.Sprintf("No logs are available for job %d in %s/%s. Logs expire after the repository's retention period, and only GitHub Actions jobs have logs.", jobID, owner, repo)), nil
+		}
+		return apiError("Failed to get job logs", err)
+	}
+
+	// The logs live behind a short-lived pre-signed URL, which must be
+	// fetched without the GitHub credentials.
+	logReq, err := http.NewRequestWithContext(ctx, http.MethodGet, logURL.String(), nil)
+	if err != nil {
+		return apiError("Failed to download job logs", err)
+	}
+
+	logResp, err := (&http.Client{Timeout: s.httpTimeout}).Do(logReq)
+	if err != nil {
+		return apiError("Failed to download job logs", err)
+	}
+	defer logResp.Body.Close()
+
+	if logResp.StatusCode != http.StatusOK {
+		return apiError("Failed to download job logs", &github.ErrorResponse{Response: logResp, Message: logResp.Status})
+	}
+
+	shown, total, err := tailLines(logResp.Body, lines)
+	if err != nil {
+		return apiError("Failed to read job logs", err)
+	}
+
+	var responseBuilder strings.Builder
+	if len(shown) < total {
+		responseBuilder.WriteString(fmt.Sprintf("Last %d of %d log lines for job %d (increase lines to see more):\n\n", len(shown), total, jobID))
+	} else {
+		responseBuilder.WriteString(fmt.Sprintf("All %d log lines for job %d:\n\n", total, jobID))
+	}
+	responseBuilder.WriteString(strings.Join(shown, "\n"))
+	responseBuilder.WriteString("\n")
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+// tailLines reads r to the end and returns its last n lines, along with the
+// total number of lines. Only n lines are held in memory at a time, so a
+// large job log is never buffered whole.
+func tailLines(r io.Reader, n int) ([]string, int, error) {
+	ring := make([]string, n)
+	total := 0
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			ring[total%n] = strings.TrimSuffix(line, "\n")
+			total++
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if total <= n {
+		return ring[:total], total, nil
+	}
+	start := total % n
+	return append(ring[start:], ring[:start]...), total, nil
+}
+
+// failedStep returns the first step of a job that failed, or nil if none did.
+func failedStep(job *github.WorkflowJob) *github.TaskStep {
+	for _, step := range job.Steps {
+		if step.GetConclusion() == "failure" {
+			return step
+		}
+	}
+	return nil
+}
+
+func (s *githubService) getWorkflowRunJobsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	owner, repo, runID, err := repoAndRunID(req)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	var jobs []*github.WorkflowJob
+	opts := &github.ListWorkflowJobsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		result, resp, err := s.restClient.Actions.ListWorkflowJobs(ctx, owner, repo, runID, opts)
+		if err != nil {
+			return apiError("Failed to list workflow jobs", err)
+		}
+		jobs = append(jobs, result.Jobs...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(jobs) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Workflow run %d in %s/%s has no jobs.", runID, owner, repo)), nil
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Workflow run %d has %d jobs:\n\n", runID, len(jobs)))
+	for _, job := range jobs {
+		conclusion := job.GetConclusion()
+		if conclusion == "" {
+			conclusion = "-"
+		}
+		responseBuilder.WriteString(fmt.Sprintf("- %s [%s / %s] (job ID %d)\n", job.GetName(), job.GetStatus(), conclusion, job.GetID()))
+		if step := failedStep(job); step != nil {
+			responseBuilder.WriteString(fmt.Sprintf("  Failed step %d: %s\n", step.GetNumber(), step.GetName()))
+		}
+		responseBuilder.WriteString(fmt.Sprintf("  %s\n", job.GetHTMLURL()))
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
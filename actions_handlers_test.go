@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTailLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		n         int
+		want      []string
+		wantTotal int
+	}{
+		{name: "empty", input: "", n: 3, want: []string{}, wantTotal: 0},
+		{name: "fewer lines than n", input: "a\nb\n", n: 3, want: []string{"a", "b"}, wantTotal: 2},
+		{name: "exactly n", input: "a\nb\nc\n", n: 3, want: []string{"a", "b", "c"}, wantTotal: 3},
+		{name: "more than n", input: "a\nb\nc\nd\ne\n", n: 3, want: []string{"c", "d", "e"}, wantTotal: 5},
+		{name: "no trailing newline", input: "a\nb\nc\nd", n: 2, want: []string{"c", "d"}, wantTotal: 4},
+		{name: "wraps several times", input: "1\n2\n3\n4\n5\n6\n7\n", n: 3, want: []string{"5", "6", "7"}, wantTotal: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := tailLines(strings.NewReader(tt.input), tt.n)
+			if err != nil {
+				t.Fatalf("tailLines: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || total != tt.wantTotal {
+				t.Errorf("tailLines = (%q, %d), want (%q, %d)", got, total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+}
+
+// jobLogServer redirects the job logs endpoint to a raw log URL on the same
+// server, as GitHub does with its pre-signed storage URLs, and serves the log
+// there with logStatus.
+func jobLogServer(logStatus int, log string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/actions/jobs/5/logs", func(w http.ResponseWriter, r *http.Request) {
+		// Location must be absolute; withREST serves everything under /api/v3.
+		w.Header().Set("Location", "http://"+r.Host+"/api/v3/raw-log")
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/raw-log", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(logStatus)
+		io.WriteString(w, log)
+	})
+	return mux
+}
+
+func TestGetCheckRunLogsHandler(t *testing.T) {
+	var log strings.Builder
+	for i := 1; i <= 500; i++ {
+		fmt.Fprintf(&log, "line %d\n", i)
+	}
+	s := withREST(t, newTestService(nil), jobLogServer(http.StatusOK, log.String()))
+
+	result, err := s.getCheckRunLogsHandler(context.Background(), toolRequest(map[string]interface{}{
+		"repo":   "owner/repo",
+		"job_id": float64(5),
+		"lines":  float64(3),
+	}))
+	if err != nil {
+		t.Fatalf("getCheckRunLogsHandler: %v", err)
+	}
+
+	want := "Last 3 of 500 log lines for job 5 (increase lines to see more):\n\nline 498\nline 499\nline 500\n"
+	if got := resultText(t, result); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestGetCheckRunLogsHandlerDownloadFailures(t *testing.T) {
+	req := toolRequest(map[string]interface{}{"repo": "owner/repo", "job_id": float64(5)})
+
+	t.Run("storage server error is a Go error", func(t *testing.T) {
+		s := withREST(t, newTestService(nil), jobLogServer(http.StatusServiceUnavailable, ""))
+		if result, err := s.getCheckRunLogsHandler(context.Background(), req); err == nil {
+			t.Fatalf("got result %+v, want a Go error", result)
+		}
+	})
+
+	t.Run("expired URL is a tool error", func(t *testing.T) {
+		s := withREST(t, newTestService(nil), jobLogServer(http.StatusForbidden, "<Error>AuthenticationFailed</Error>"))
+		result, err := s.getCheckRunLogsHandler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("got Go error %v, want a tool error", err)
+		}
+		if text := resultText(t, result); !result.IsError || !strings.Contains(text, "403 Forbidden") {
+			t.Errorf("result = %q, want a tool error naming the status", text)
+		}
+	})
+}
+
+func TestGetCheckRunLogsHandlerUsesConfiguredTimeout(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/actions/jobs/5/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "http://"+r.Host+"/api/v3/raw-log")
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/raw-log", func(w http.ResponseWriter, r *http.Request) {
+		// Stall until the client gives up.
+		<-r.Context().Done()
+	})
+	s := withREST(t, newTestService(nil), mux)
+	s.httpTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	result, err := s.getCheckRunLogsHandler(context.Background(), toolRequest(map[string]interface{}{"repo": "owner/repo", "job_id": float64(5)}))
+	if err == nil {
+		t.Fatalf("got result %+v, want a timeout error", result)
+	}
+	if elapsed := time.Since(start); elapsed > defaultHTTPTimeout/2 {
+		t.Errorf("download took %v, want it bounded by the configured timeout", elapsed)
+	}
+}
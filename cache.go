@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultThreadCacheTTL is how long fetched review threads are reused unless
+// GITHUB_CACHE_TTL says otherwise.
+const defaultThreadCacheTTL = 30 * time.Second
+
+// threadCache keeps recently fetched review threads in memory so that calling
+// several comment tools on the same pull request in a row only queries
+// GitHub once.
+type threadCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]threadCacheEntry
+}
+
+type threadCacheEntry struct {
+	threads      []reviewThread
+	fetchedAt    time.Time
+	withDiffHunk bool
+}
+
+func newThreadCache(ttl time.Duration) *threadCache {
+	return &threadCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]threadCacheEntry{},
+	}
+}
+
+// threadCacheKey is case-insensitive in owner and repo, as GitHub is, so a
+// URL typed in a different case still hits or invalidates the same entry.
+func threadCacheKey(owner, repo string, number int) string {
+	return strings.ToLower(fmt.Sprintf("%s/%s/%d", owner, repo, number))
+}
+
+// get returns the fresh threads cached under key. When withDiffHunk is set,
+// only an entry that was fetched with diff hunks will do.
+func (c *threadCache) get(key string, withDiffHunk bool) ([]reviewThread, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || (withDiffHunk && !entry.withDiffHunk) {
+		return nil, false
+	}
+
+	if c.now().Sub(entry.fetchedAt) >= c.ttl {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return entry.threads, true
+}
+
+// set stores threads under key, recording whether they include diff hunks.
+// Expired entries are swept at the same time, so pull requests that are never
+// read again do not stay in memory.
+func (c *threadCache) set(key string, threads []reviewThread, withDiffHunk bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) >= c.ttl {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = threadCacheEntry{
+		threads:      threads,
+		fetchedAt:    now,
+		withDiffHunk: withDiffHunk,
+	}
+}
+
+// invalidate drops the cached threads for key. Tools that change review
+// threads call it so that the next read sees their change.
+func (c *threadCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+func TestGetReviewThreadsServesCacheWithinTTL(t *testing.T) {
+	gql := &fakeGraphQL{query: threadPages(3, 100)}
+	s := newTestService(gql)
+
+	now := time.Now()
+	s.threadCache.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		threads, _, err := s.getReviewThreads(context.Background(), "owner", "repo", 1, false)
+		if err != nil {
+			t.Fatalf("getReviewThreads: %v", err)
+		}
+		if len(threads) != 3 {
+			t.Fatalf("call %d: got %d threads, want 3", i, len(threads))
+		}
+	}
+	if got := gql.callCount(); got != 1 {
+		t.Fatalf("made %d queries within the TTL, want 1", got)
+	}
+
+	now = now.Add(defaultThreadCacheTTL)
+	if _, _, err := s.getReviewThreads(context.Background(), "owner", "repo", 1, false); err != nil {
+		t.Fatalf("getReviewThreads: %v", err)
+	}
+	if got := gql.callCount(); got != 2 {
+		t.Errorf("made %d queries after the TTL expired, want 2", got)
+	}
+}
+
+func TestThreadCacheSetSweepsExpiredEntries(t *testing.T) {
+	c := newThreadCache(time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.set("a", nil, false)
+	now = now.Add(2 * time.Minute)
+	c.set("b", nil, false)
+
+	if _, ok := c.entries["a"]; ok {
+		t.Error("expired entry was not swept by set")
+	}
+	if _, ok := c.entries["b"]; !ok {
+		t.Error("fresh entry is missing")
+	}
+}
+
+func TestResolveReviewThreadInvalidatesCache(t *testing.T) {
+	gql := &fakeGraphQL{}
+	gql.query = func(q interface{}, variables map[string]interface{}) error {
+		query, ok := q.(*reviewThreadStatusQuery)
+		if !ok {
+			return fmt.Errorf("unexpected query %T", q)
+		}
+		pr := &query.Node.PullRequestReviewThread.PullRequest
+		pr.Number = 5
+		pr.Repository.Name = "Repo"
+		pr.Repository.Owner.Login = "Owner"
+		return nil
+	}
+	gql.mutate = func(m interface{}, input githubv4.Input, variables map[string]interface{}) error {
+		return nil
+	}
+	s := newTestService(gql)
+
+	key := threadCacheKey("owner", "repo", 5)
+	s.threadCache.set(key, []reviewThread{{ID: "thread-1"}}, false)
+
+	result, err := s.resolveReviewThreadHandler(context.Background(), toolRequest(map[string]interface{}{"thread_id": "thread-1"}))
+	if err != nil {
+		t.Fatalf("resolveReviewThreadHandler: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("resolveReviewThreadHandler returned a tool error: %v", result.Content)
+	}
+
+	if _, ok := s.threadCache.get(key, false); ok {
+		t.Error("cached threads survived resolving a thread on the pull request")
+	}
+}
+
+func TestGetCommentsByFileUsesThreadCache(t *testing.T) {
+	gql := &fakeGraphQL{query: threadPages(2, 100)}
+	s := newTestService(gql)
+	ctx := context.Background()
+	prURL := "https://github.com/owner/repo/pull/1"
+
+	if _, err := s.getUnresolvedCommentsHandler(ctx, toolRequest(map[string]interface{}{"pull_request_url": prURL})); err != nil {
+		t.Fatalf("getUnresolvedCommentsHandler: %v", err)
+	}
+	if _, err := s.getCommentsByFileHandler(ctx, toolRequest(map[string]interface{}{"pull_request_url": prURL})); err != nil {
+		t.Fatalf("getCommentsByFileHandler: %v", err)
+	}
+	if got := gql.callCount(); got != 1 {
+		t.Errorf("made %d queries, want get_comments_by_file served from the cache", got)
+	}
+
+	if _, err := s.getCommentsByFileHandler(ctx, toolRequest(map[string]interface{}{"pull_request_url": prURL, "no_cache": true})); err != nil {
+		t.Fatalf("getCommentsByFileHandler: %v", err)
+	}
+	if got := gql.callCount(); got != 2 {
+		t.Errorf("made %d queries, want no_cache to refetch", got)
+	}
+}
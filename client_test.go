@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// recordingTransport answers every request with an empty GraphQL response and
+// remembers the URLs it was sent to.
+type recordingTransport struct {
+	urls []string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.urls = append(t.urls, req.URL.String())
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"data":{"viewer":{"login":"octocat"}}}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestNewClientsEndpoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		baseURL    string
+		uploadURL  string
+		wantREST   string
+		wantUpload string
+		wantGraph  string
+	}{
+		{
+			name:       "github.com",
+			wantREST:   "https://api.github.com/",
+			wantUpload: "https://uploads.github.com/",
+			wantGraph:  "https://api.github.com/graphql",
+		},
+		{
+			name:       "enterprise host",
+			baseURL:    "https://ghe.example.com",
+			wantREST:   "https://ghe.example.com/api/v3/",
+			wantUpload: "https://ghe.example.com/api/uploads/",
+			wantGraph:  "https://ghe.example.com/api/graphql",
+		},
+		{
+			name:       "enterprise API path with trailing slash",
+			baseURL:    "https://ghe.example.com/api/v3/",
+			wantREST:   "https://ghe.example.com/api/v3/",
+			wantUpload: "https://ghe.example.com/api/uploads/",
+			wantGraph:  "https://ghe.example.com/api/graphql",
+		},
+		{
+			name:       "enterprise with separate upload host",
+			baseURL:    "https://ghe.example.com/api/v3",
+			uploadURL:  "https://uploads.ghe.example.com",
+			wantREST:   "https://ghe.example.com/api/v3/",
+			wantUpload: "https://uploads.ghe.example.com/api/uploads/",
+			wantGraph:  "https://ghe.example.com/api/graphql",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &recordingTransport{}
+			restClient, graphqlClient, err := newClients(&http.Client{Transport: transport}, tt.baseURL, tt.uploadURL)
+			if err != nil {
+				t.Fatalf("newClients: %v", err)
+			}
+
+			if got := restClient.BaseURL.String(); got != tt.wantREST {
+				t.Errorf("REST base URL = %q, want %q", got, tt.wantREST)
+			}
+			if got := restClient.UploadURL.String(); got != tt.wantUpload {
+				t.Errorf("upload URL = %q, want %q", got, tt.wantUpload)
+			}
+
+			var query viewerQuery
+			if err := graphqlClient.Query(context.Background(), &query, nil); err != nil {
+				t.Fatalf("GraphQL query: %v", err)
+			}
+			if len(transport.urls) != 1 || transport.urls[0] != tt.wantGraph {
+				t.Errorf("GraphQL requests went to %v, want [%s]", transport.urls, tt.wantGraph)
+			}
+		})
+	}
+}
+
+func TestNewClientsRejectsInvalidBaseURL(t *testing.T) {
+	if _, _, err := newClients(http.DefaultClient, "://not a url", ""); err == nil {
+		t.Fatal("newClients accepted an invalid base URL")
+	}
+}
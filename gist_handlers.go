@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// gistFilesFromRequest reads the gist's files from either a "files" JSON
+// object mapping filenames to contents, or a single "filename" and "content"
+// pair.
+func gistFilesFromRequest(req mcp.CallToolRequest) (map[github.GistFilename]github.GistFile, error) {
+	contents := map[string]string{}
+	if filesArg := strings.TrimSpace(req.GetString("files", "")); filesArg != "" {
+		if err := json.Unmarshal([]byte(filesArg), &contents); err != nil {
+			return nil, fmt.Errorf("invalid files: expected a JSON object mapping filenames to contents: %v", err)
+		}
+	}
+
+	if filename := strings.TrimSpace(req.GetString("filename", "")); filename != "" {
+		contents[filename] = req.GetString("content", "")
+	}
+
+	if len(contents) == 0 {
+		return nil, fmt.Errorf("provide either filename and content, or files")
+	}
+
+	files := make(map[github.GistFilename]github.GistFile, len(contents))
+	for filename, content := range contents {
+		if strings.TrimSpace(content) == "" {
+			return nil, fmt.Errorf("file %q is empty; GitHub does not allow empty gist files", filename)
+		}
+		files[github.GistFilename(filename)] = github.GistFile{Content: github.String(content)}
+	}
+
+	return files, nil
+}
+
+func (s *githubService) createGistHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.isApp {
+		return toolError("Gists belong to users, so they cannot be created with GitHub App credentials"), nil
+	}
+
+	files, err := gistFilesFromRequest(req)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	gist := &github.Gist{
+		Public: github.Bool(req.GetBool("public", false)),
+		Files:  files,
+	}
+	if description := req.GetString("description", ""); description != "" {
+		gist.Description = github.String(description)
+	}
+
+	created, _, err := s.restClient.Gists.Create(ctx, gist)
+	if err != nil {
+		return apiError("Failed to create gist", err)
+	}
+
+	visibility := "secret"
+	if created.GetPublic() {
+		visibility = "public"
+	}
+
+	filenames := make([]string, 0, len(created.Files))
+	for filename := range created.Files {
+		filenames = append(filenames, string(filename))
+	}
+	sort.Strings(filenames)
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Created %s gist: %s\n", visibility, created.GetHTMLURL()))
+	for _, filename := range filenames {
+		file := created.Files[github.GistFilename(filename)]
+		responseBuilder.WriteString(fmt.Sprintf("- %s (raw): %s\n", filename, file.GetRawURL()))
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
@@ -2,107 +2,394 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/bradleyfalzon/ghinstallation/v2"
 	"github.com/google/go-github/v62/github"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
+	"golang.org/x/sync/errgroup"
 )
 
-var prURLRegex = regexp.MustCompile(`https://github\.com/([^/]+)/([^/]+)/pull/(\d+)`)
+// prURLRegex matches a pull request URL on any GitHub host, tolerating the
+// sub-page paths (/files, /commits), query strings, fragments and trailing
+// slashes that show up in URLs copied from the browser.
+var prURLRegex = regexp.MustCompile(`^https?://[^/]+/([^/]+)/([^/]+)/pull/(\d+)(?:[/?#].*)?$`)
+
+// defaultMaxRetries is the number of times a rate-limited request is retried
+// unless GITHUB_MAX_RETRIES says otherwise.
+const defaultMaxRetries = 3
+
+// defaultMaxGraphQLPages caps the GraphQL pages fetched for one pull
+// request's review threads unless GITHUB_MAX_GRAPHQL_PAGES says otherwise.
+const defaultMaxGraphQLPages = 20
+
+// defaultHTTPTimeout bounds every GitHub API request unless
+// GITHUB_HTTP_TIMEOUT says otherwise.
+const defaultHTTPTimeout = 30 * time.Second
+
+// graphqlQuerier is the subset of *githubv4.Client the handlers use. It lets
+// tests substitute a fake that fills in query results without network access.
+type graphqlQuerier interface {
+	Query(ctx context.Context, q interface{}, variables map[string]interface{}) error
+	Mutate(ctx context.Context, m interface{}, input githubv4.Input, variables map[string]interface{}) error
+}
 
 type githubService struct {
 	restClient    *github.Client
-	graphqlClient *githubv4.Client
+	graphqlClient graphqlQuerier
+	isApp         bool
+	threadCache   *threadCache
+	reviewBatches *reviewBatches
+
+	// login is the authenticated user's login, captured when the credentials
+	// are validated. It is empty for GitHub App installations.
+	login string
+
+	// maxGraphQLPages caps the GraphQL pages fetched for one pull request's
+	// review threads.
+	maxGraphQLPages int
+
+	// httpTimeout is the configured request timeout, for downloads that
+	// bypass the authorized client.
+	httpTimeout time.Duration
 }
 
-func newGithubService() (*githubService, error) {
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		return nil, fmt.Errorf("GITHUB_TOKEN environment variable is not set")
+// viewer returns the authenticated user's login for use in search
+// qualifiers, falling back to GitHub's "@me" shorthand when it is unknown.
+func (s *githubService) viewer() string {
+	if s.login != "" {
+		return s.login
+	}
+	return "@me"
+}
+
+// newGithubService builds a service on top of an authorized HTTP client,
+// applying the retry, timeout and cache settings from the environment, and
+// checks that the credentials work.
+func newGithubService(ctx context.Context, authorizedClient *http.Client, isApp bool) (*githubService, error) {
+	maxRetries := defaultMaxRetries
+	if value := os.Getenv("GITHUB_MAX_RETRIES"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid GITHUB_MAX_RETRIES %q: must be a non-negative integer", value)
+		}
+		maxRetries = parsed
+	}
+	authorizedClient.Transport = newRetryTransport(newLoggingTransport(authorizedClient.Transport), maxRetries)
+
+	authorizedClient.Timeout = defaultHTTPTimeout
+	if value := os.Getenv("GITHUB_HTTP_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid GITHUB_HTTP_TIMEOUT %q: must be a positive duration such as 30s", value)
+		}
+		authorizedClient.Timeout = timeout
+	}
+
+	githubClient, graphqlClient, err := newClients(authorizedClient, os.Getenv("GITHUB_BASE_URL"), os.Getenv("GITHUB_UPLOAD_URL"))
+	if err != nil {
+		return nil, err
+	}
+
+	cacheTTL := defaultThreadCacheTTL
+	if value := os.Getenv("GITHUB_CACHE_TTL"); value != "" {
+		cacheTTL, err = time.ParseDuration(value)
+		if err != nil || cacheTTL < 0 {
+			return nil, fmt.Errorf("invalid GITHUB_CACHE_TTL %q: must be a duration such as 30s, or 0 to disable caching", value)
+		}
+	}
+
+	maxGraphQLPages := defaultMaxGraphQLPages
+	if value := os.Getenv("GITHUB_MAX_GRAPHQL_PAGES"); value != "" {
+		maxGraphQLPages, err = strconv.Atoi(value)
+		if err != nil || maxGraphQLPages < 1 {
+			return nil, fmt.Errorf("invalid GITHUB_MAX_GRAPHQL_PAGES %q: must be a positive integer", value)
+		}
+	}
+
+	skipValidation := false
+	if value := os.Getenv("GITHUB_SKIP_VALIDATION"); value != "" {
+		skipValidation, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GITHUB_SKIP_VALIDATION %q: must be true or false", value)
+		}
+	}
+
+	// Without validation the login stays unknown, and searches fall back to
+	// GitHub's "@me" shorthand.
+	login := ""
+	if skipValidation {
+		slog.Warn("Skipping GitHub credential validation; invalid credentials will only surface when a tool is called")
+	} else {
+		login, err = validateCredentials(ctx, githubClient, isApp)
+		if err != nil {
+			return nil, fmt.Errorf("GitHub authentication failed: %v", err)
+		}
+	}
+
+	return &githubService{
+		restClient:      githubClient,
+		graphqlClient:   graphqlClient,
+		isApp:           isApp,
+		threadCache:     newThreadCache(cacheTTL),
+		reviewBatches:   newReviewBatches(),
+		login:           login,
+		maxGraphQLPages: maxGraphQLPages,
+		httpTimeout:     authorizedClient.Timeout,
+	}, nil
+}
+
+// newClients builds the REST and GraphQL clients on top of httpClient. When
+// baseURL is set, both target that GitHub Enterprise Server instead of
+// github.com; tests use the same seam to point them at an httptest.Server.
+func newClients(httpClient *http.Client, baseURL, uploadURL string) (*github.Client, graphqlQuerier, error) {
+	if baseURL == "" {
+		return github.NewClient(httpClient), githubv4.NewClient(httpClient), nil
+	}
+
+	// Uploads live under the host root, next to /api/v3 rather than inside it.
+	if uploadURL == "" {
+		uploadURL = enterpriseHost(baseURL)
+	}
+
+	restClient, err := github.NewClient(httpClient).WithEnterpriseURLs(baseURL, uploadURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid GITHUB_BASE_URL: %v", err)
+	}
+
+	return restClient, githubv4.NewEnterpriseClient(enterpriseGraphQLURL(baseURL), httpClient), nil
+}
+
+// newAuthorizedClient builds the HTTP client shared by the REST and GraphQL
+// clients. GitHub App installation credentials are used when GITHUB_APP_ID is
+// set; otherwise the personal access token in GITHUB_TOKEN is used. The
+// returned flag reports whether the client authenticates as an app.
+func newAuthorizedClient(ctx context.Context) (*http.Client, bool, error) {
+	if os.Getenv("GITHUB_APP_ID") != "" {
+		transport, err := newAppTransport()
+		if err != nil {
+			return nil, false, err
+		}
+		return &http.Client{Transport: transport}, true, nil
+	}
+
+	token, err := readToken()
+	if err != nil {
+		return nil, false, err
 	}
 
-	ctx := context.Background()
+	return newTokenClient(ctx, token), false, nil
+}
+
+// newTokenClient returns an HTTP client that authenticates with a personal
+// access token.
+func newTokenClient(ctx context.Context, token string) *http.Client {
 	tokenSource := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
-	authorizedClient := oauth2.NewClient(ctx, tokenSource)
+	return oauth2.NewClient(ctx, tokenSource)
+}
 
-	githubClient := github.NewClient(authorizedClient)
-	graphqlClient := githubv4.NewClient(authorizedClient)
+// readToken returns the personal access token from GITHUB_TOKEN, or failing
+// that from the file named by GITHUB_TOKEN_FILE, as written by secret managers.
+func readToken() (string, error) {
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token, nil
+	}
 
-	if err := validateCredentials(ctx, githubClient); err != nil {
-		return nil, fmt.Errorf("GitHub authentication failed: %v", err)
+	path := os.Getenv("GITHUB_TOKEN_FILE")
+	if path == "" {
+		return "", fmt.Errorf("neither GITHUB_TOKEN nor GITHUB_TOKEN_FILE environment variable is set")
 	}
 
-	return &githubService{
-		restClient:    githubClient,
-		graphqlClient: graphqlClient,
-	}, nil
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read GITHUB_TOKEN_FILE: %v", err)
+	}
+
+	token := strings.TrimSpace(string(contents))
+	if token == "" {
+		return "", fmt.Errorf("GITHUB_TOKEN_FILE %s is empty", path)
+	}
+	registerSecret(token)
+
+	return token, nil
+}
+
+// newAppTransport authenticates as a GitHub App installation using
+// GITHUB_APP_ID, GITHUB_APP_INSTALLATION_ID and GITHUB_APP_PRIVATE_KEY, which
+// holds either the PEM-encoded key itself or the path to a key file.
+func newAppTransport() (http.RoundTripper, error) {
+	appID, err := strconv.ParseInt(os.Getenv("GITHUB_APP_ID"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GITHUB_APP_ID: %v", err)
+	}
+
+	installationID, err := strconv.ParseInt(os.Getenv("GITHUB_APP_INSTALLATION_ID"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or missing GITHUB_APP_INSTALLATION_ID: %v", err)
+	}
+
+	privateKey := os.Getenv("GITHUB_APP_PRIVATE_KEY")
+	if privateKey == "" {
+		return nil, fmt.Errorf("GITHUB_APP_PRIVATE_KEY environment variable is not set")
+	}
+
+	key := []byte(privateKey)
+	if !strings.HasPrefix(strings.TrimSpace(privateKey), "-----BEGIN") {
+		key, err = os.ReadFile(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GITHUB_APP_PRIVATE_KEY file: %v", err)
+		}
+	}
+
+	transport, err := ghinstallation.New(http.DefaultTransport, appID, installationID, key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub App credentials: %v", err)
+	}
+
+	if baseURL := os.Getenv("GITHUB_BASE_URL"); baseURL != "" {
+		transport.BaseURL = enterpriseAPIURL(baseURL)
+	}
+
+	return transport, nil
 }
 
-func validateCredentials(ctx context.Context, client *github.Client) error {
-	_, resp, err := client.Users.Get(ctx, "")
+// enterpriseAPIURL and enterpriseGraphQLURL derive the REST and GraphQL
+// endpoints of a GitHub Enterprise Server from its base URL, which may or
+// may not include the /api/v3 suffix.
+func enterpriseAPIURL(baseURL string) string {
+	return enterpriseHost(baseURL) + "/api/v3"
+}
+
+func enterpriseGraphQLURL(baseURL string) string {
+	return enterpriseHost(baseURL) + "/api/graphql"
+}
+
+func enterpriseHost(baseURL string) string {
+	base := strings.TrimSuffix(baseURL, "/")
+	return strings.TrimSuffix(base, "/api/v3")
+}
+
+// validateCredentials makes a cheap authenticated request to confirm the
+// configured credentials work, returning the authenticated user's login. App
+// installations cannot read /user, so they list the installation's
+// repositories instead and report no login.
+func validateCredentials(ctx context.Context, client *github.Client, isApp bool) (string, error) {
+	var resp *github.Response
+	var err error
+	login := ""
+	if isApp {
+		_, resp, err = client.Apps.ListRepos(ctx, &github.ListOptions{PerPage: 1})
+	} else {
+		var user *github.User
+		user, resp, err = client.Users.Get(ctx, "")
+		login = user.GetLogin()
+	}
 	if err != nil {
-		return fmt.Errorf("authentication test failed: %v", err)
+		return "", fmt.Errorf("authentication test failed: %v", err)
 	}
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		return fmt.Errorf("invalid or expired token")
+		return "", fmt.Errorf("invalid or expired token")
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected API response: %s", resp.Status)
+		return "", fmt.Errorf("unexpected API response: %s", resp.Status)
 	}
 
-	return nil
+	return login, nil
 }
 
 func (s *githubService) listPullRequestsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	state := req.GetString("state", "open")
-	var queryParts []string
-	queryParts = append(queryParts, "is:pr", "author:@me")
-
-	if state == "open" || state == "closed" {
-		queryParts = append(queryParts, fmt.Sprintf("is:%s", state))
+	format, err := getFormat(req)
+	if err != nil {
+		return toolError(err.Error()), nil
 	}
 
-	query := strings.Join(queryParts, " ")
+	author := req.GetString("author", "")
+	if author == "" || author == "@me" {
+		author = s.viewer()
+	}
 
-	opts := &github.SearchOptions{
-		Sort:  "updated",
-		Order: "desc",
-		ListOptions: github.ListOptions{
-			PerPage: 15,
-		},
+	query, err := pullRequestSearchQuery(req, state, author)
+	if err != nil {
+		return toolError(err.Error()), nil
 	}
 
-	result, resp, err := s.restClient.Search.Issues(ctx, query, opts)
+	limit := getLimit(req, 15)
+
+	opts, err := pullRequestSearchOptions(req, limit)
 	if err != nil {
-		log.Printf("Error searching GitHub: %v", err)
-		return mcp.NewToolResultError(fmt.Sprintf("Error searching GitHub: %v", err)), nil
+		return toolError(err.Error()), nil
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return mcp.NewToolResultError(fmt.Sprintf("GitHub API returned non-200 status: %s", resp.Status)), nil
+	var issues []*github.Issue
+	total := 0
+	for {
+		result, resp, err := s.restClient.Search.Issues(ctx, query, opts)
+		if err != nil {
+			slog.Warn("Error searching GitHub", "query", query, "error", redactSecrets(err.Error()))
+			return apiError("Error searching GitHub", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return toolError(fmt.Sprintf("GitHub API returned non-200 status: %s", resp.Status)), nil
+		}
+
+		total = result.GetTotal()
+		issues = append(issues, result.Issues...)
+
+		if len(issues) >= limit || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(issues) > limit {
+		issues = issues[:limit]
+	}
+
+	if format == "json" {
+		output := pullRequestListOutput{
+			Total:        total,
+			Returned:     len(issues),
+			State:        state,
+			PullRequests: []pullRequestSummary{},
+		}
+		for _, issue := range issues {
+			output.PullRequests = append(output.PullRequests, pullRequestSummary{
+				Number: issue.GetNumber(),
+				Title:  issue.GetTitle(),
+				State:  issue.GetState(),
+				URL:    issue.GetHTMLURL(),
+			})
+		}
+		return jsonResult(output)
 	}
 
-	if result.GetTotal() == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("No pull requests found with state: %s", state)), nil
+	if total == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No pull requests authored by %s found with state: %s", author, state)), nil
 	}
 
-	log.Printf("Found %d PRs.", result.GetTotal())
+	slog.Debug("Listed pull requests", "total", total, "returned", len(issues))
 	var responseBuilder strings.Builder
-	responseBuilder.WriteString(fmt.Sprintf("Found %d pull requests (state: %s):\n\n", result.GetTotal(), state))
+	responseBuilder.WriteString(fmt.Sprintf("Found %d pull requests authored by %s (state: %s), showing %d:\n\n", total, author, state, len(issues)))
 
-	for _, issue := range result.Issues {
+	for _, issue := range issues {
 		responseBuilder.WriteString(fmt.Sprintf("- [State: %s] %s\n  %s\n",
 			issue.GetState(),
 			issue.GetTitle(),
@@ -110,11 +397,12 @@ func (s *githubService) listPullRequestsHandler(ctx context.Context, req mcp.Cal
 		))
 	}
 
+	responseBuilder.WriteString(paginationFooter(len(issues), total))
 	return mcp.NewToolResultText(responseBuilder.String()), nil
 }
 
 func parsePRURL(url string) (owner string, repo string, number int, err error) {
-	matches := prURLRegex.FindStringSubmatch(url)
+	matches := prURLRegex.FindStringSubmatch(strings.TrimSpace(url))
 	if len(matches) != 4 {
 		return "", "", 0, fmt.Errorf("invalid PR URL format. Expected: .../owner/repo/pull/123")
 	}
@@ -129,155 +417,2659 @@ func parsePRURL(url string) (owner string, repo string, number int, err error) {
 	return owner, repo, number, nil
 }
 
-func (s *githubService) getUnresolvedCommentsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	prURL, err := req.RequireString("pull_request_url")
-	if err != nil {
-		return mcp.NewToolResultError("Missing required argument: pull_request_url"), nil
+// pullRequestSearchQuery assembles the search query for list_pull_requests
+// from the author and the remaining filter arguments.
+func pullRequestSearchQuery(req mcp.CallToolRequest, state, author string) (string, error) {
+	var queryParts []string
+	queryParts = append(queryParts, "is:pr", fmt.Sprintf("author:%s", author))
+
+	if state == "open" || state == "closed" {
+		queryParts = append(queryParts, fmt.Sprintf("is:%s", state))
 	}
 
-	owner, repo, prNumber, err := parsePRURL(prURL)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	if assignee := req.GetString("assignee", ""); assignee != "" {
+		queryParts = append(queryParts, fmt.Sprintf("assignee:%s", assignee))
 	}
 
-	var query prCommentsQuery
-	variables := map[string]interface{}{
-		"owner":    githubv4.String(owner),
-		"repo":     githubv4.String(repo),
-		"prNumber": githubv4.Int(prNumber),
+	if repoArg := req.GetString("repo", ""); repoArg != "" {
+		owner, repo, err := parseRepo(repoArg)
+		if err != nil {
+			return "", fmt.Errorf("invalid repo: %v", err)
+		}
+		queryParts = append(queryParts, fmt.Sprintf("repo:%s/%s", owner, repo))
 	}
 
-	if err := s.graphqlClient.Query(ctx, &query, variables); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("GitHub GraphQL query failed: %v", err)), nil
+	for _, filter := range []struct{ arg, qualifier string }{
+		{"created_after", "created"},
+		{"updated_after", "updated"},
+	} {
+		value := strings.TrimSpace(req.GetString(filter.arg, ""))
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "", fmt.Errorf("invalid %s %q. Expected a date in YYYY-MM-DD format", filter.arg, value)
+		}
+		queryParts = append(queryParts, fmt.Sprintf("%s:>=%s", filter.qualifier, value))
 	}
 
-	var responseBuilder strings.Builder
-	unresolvedCount := 0
-	for _, thread := range query.Repository.PullRequest.ReviewThreads.Nodes {
-		if !thread.IsResolved {
-			unresolvedCount++
-			if len(thread.Comments.Nodes) > 0 {
-				firstComment := thread.Comments.Nodes[0]
-				responseBuilder.WriteString(fmt.Sprintf(
-					"Unresolved Thread on: %s (Line %d)\n",
-					string(firstComment.Path),
-					int(firstComment.Line),
-				))
+	return strings.Join(queryParts, " "), nil
+}
 
-				for _, comment := range thread.Comments.Nodes {
-					fullBody := string(comment.Body)
-					if len(fullBody) > 200 {
-						lines := strings.Split(fullBody, "\n")
-						if len(lines) > 3 {
-							preview := strings.Join(lines[:3], "\n")
-							responseBuilder.WriteString(fmt.Sprintf(
-								"  - @%s: %s\n… +%d lines (ctrl+o to expand)\n",
-								string(comment.Author.Login),
-								preview,
-								len(lines)-3,
-							))
-						} else {
-							responseBuilder.WriteString(fmt.Sprintf(
-								"  - @%s: %s\n",
-								string(comment.Author.Login),
-								fullBody,
-							))
-						}
-					} else {
-						responseBuilder.WriteString(fmt.Sprintf(
-							"  - @%s: %s\n",
-							string(comment.Author.Login),
-							fullBody,
-						))
-					}
-				}
-				responseBuilder.WriteString(fmt.Sprintf("  (Thread Link: %s)\n\n", firstComment.URL.String())) // .URL is a URI
-			}
-		}
+// pullRequestSearchOptions builds the search options for list_pull_requests
+// from its optional "sort" and "order" arguments, defaulting to the most
+// recently updated pull requests first.
+func pullRequestSearchOptions(req mcp.CallToolRequest, limit int) (*github.SearchOptions, error) {
+	sortBy := req.GetString("sort", "updated")
+	if sortBy != "created" && sortBy != "updated" && sortBy != "comments" {
+		return nil, fmt.Errorf("invalid sort %q. Expected created, updated or comments", sortBy)
 	}
 
-	if unresolvedCount == 0 {
-		return mcp.NewToolResultText("No unresolved comments found on that PR."), nil
+	order := req.GetString("order", "desc")
+	if order != "asc" && order != "desc" {
+		return nil, fmt.Errorf("invalid order %q. Expected asc or desc", order)
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d unresolved comment threads:\n\n%s", unresolvedCount, responseBuilder.String())), nil
+	return &github.SearchOptions{
+		Sort:  sortBy,
+		Order: order,
+		ListOptions: github.ListOptions{
+			PerPage: limit,
+		},
+	}, nil
+}
+
+// getFormat reads the optional "format" argument, which selects between the
+// default human-readable text output and structured JSON.
+func getFormat(req mcp.CallToolRequest) (string, error) {
+	format := req.GetString("format", "text")
+	if format != "text" && format != "json" {
+		return "", fmt.Errorf("invalid format %q. Expected text or json", format)
+	}
+	return format, nil
 }
 
-func (s *githubService) getFullCommentsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	prURL, err := req.RequireString("pull_request_url")
+func jsonResult(v interface{}) (*mcp.CallToolResult, error) {
+	payload, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
-		return mcp.NewToolResultError("Missing required argument: pull_request_url"), nil
+		return toolError(fmt.Sprintf("Failed to encode JSON output: %v", err)), nil
 	}
+	return mcp.NewToolResultText(string(payload)), nil
+}
 
-	unresolvedOnlyStr := req.GetString("unresolved_only", "false")
-	unresolvedOnly := unresolvedOnlyStr == "true"
+// maxListLimit caps the number of results any listing tool will collect.
+const maxListLimit = 100
 
-	owner, repo, prNumber, err := parsePRURL(prURL)
+// getLimit reads the optional "limit" argument, falling back to defaultLimit
+// and clamping the result to the range [1, maxListLimit].
+func getLimit(req mcp.CallToolRequest, defaultLimit int) int {
+	limit := req.GetInt("limit", defaultLimit)
+	if limit < 1 {
+		return defaultLimit
+	}
+	if limit > maxListLimit {
+		return maxListLimit
+	}
+	return limit
+}
+
+// unknownTotal is passed to paginationFooter by listings that know more
+// results exist but cannot tell how many.
+const unknownTotal = -1
+
+// paginationFooter tells the user when a listing was capped by its limit, so
+// a short list is not mistaken for the complete set. It returns an empty
+// string when every result was shown.
+func paginationFooter(shown, total int) string {
+	if total == unknownTotal {
+		return fmt.Sprintf("\nShowing %d of more than %d (more available — increase limit)\n", shown, shown)
+	}
+	if shown >= total {
+		return ""
+	}
+	return fmt.Sprintf("\nShowing %d of %d (more available — increase limit)\n", shown, total)
+}
+
+// parseRepo splits an "owner/name" repository reference into its parts.
+func parseRepo(fullName string) (owner string, repo string, err error) {
+	parts := strings.Split(strings.TrimSpace(fullName), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid repository format %q. Expected: owner/name", fullName)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// threadFetchStats describes the review thread queries behind a result: their
+// rate limit cost summed over every page, and whether the page cap cut the
+// fetch short.
+type threadFetchStats struct {
+	Cost      int
+	Remaining int
+	Cached    bool
+	Truncated bool
+	PageCap   int
+}
+
+// costHeader formats the cost for the top of a tool result.
+func (c threadFetchStats) costHeader() string {
+	if c.Cached {
+		return "GraphQL cost: 0 points (served from cache)\n\n"
+	}
+	return fmt.Sprintf("GraphQL cost: %d points (%d remaining)\n\n", c.Cost, c.Remaining)
+}
+
+// truncationWarning is appended to a tool result when the page cap stopped
+// the fetch, and is empty otherwise.
+func (c threadFetchStats) truncationWarning() string {
+	if !c.Truncated {
+		return ""
+	}
+	return fmt.Sprintf("\nWarning: stopped after %d GraphQL pages (GITHUB_MAX_GRAPHQL_PAGES); some threads or comments are missing.\n", c.PageCap)
+}
+
+// pageBudget caps the GraphQL pages a single fetch may request, so a
+// pathological pull request cannot trigger hundreds of queries.
+type pageBudget struct {
+	remaining int
+	exhausted bool
+}
+
+// take reserves one page, reporting false once the budget is spent.
+func (b *pageBudget) take() bool {
+	if b.remaining <= 0 {
+		b.exhausted = true
+		return false
+	}
+	b.remaining--
+	return true
+}
+
+// getReviewThreads returns the review threads of a pull request, serving
+// them from the thread cache when a fresh copy is available. noCache forces a
+// refetch, which then refreshes the cache. The stats report what the queries
+// cost and whether the page cap truncated the result; callers must surface
+// stats.truncationWarning() so partial data is never shown as complete.
+// Comments come without their diff hunks.
+func (s *githubService) getReviewThreads(ctx context.Context, owner, repo string, number int, noCache bool) ([]reviewThread, threadFetchStats, error) {
+	return s.loadReviewThreads(ctx, owner, repo, number, noCache, false)
+}
+
+// getReviewThreadsWithDiffHunks is getReviewThreads for the tools that show
+// the diff hunk a thread was started on.
+func (s *githubService) getReviewThreadsWithDiffHunks(ctx context.Context, owner, repo string, number int, noCache bool) ([]reviewThread, threadFetchStats, error) {
+	return s.loadReviewThreads(ctx, owner, repo, number, noCache, true)
+}
+
+func (s *githubService) loadReviewThreads(ctx context.Context, owner, repo string, number int, noCache, withDiffHunk bool) ([]reviewThread, threadFetchStats, error) {
+	key := threadCacheKey(owner, repo, number)
+	if !noCache {
+		if threads, ok := s.threadCache.get(key, withDiffHunk); ok {
+			return threads, threadFetchStats{Cached: true}, nil
+		}
+	}
+
+	threads, stats, err := s.queryReviewThreads(ctx, owner, repo, number, withDiffHunk)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+		return nil, threadFetchStats{}, err
 	}
 
-	var query prCommentsQuery
+	// A truncated result is not cached, so the next call tries again in full
+	// rather than silently serving the partial threads.
+	if !stats.Truncated {
+		s.threadCache.set(key, threads, withDiffHunk)
+	}
+	return threads, stats, nil
+}
+
+// fetchReviewThreads pages through every review thread on a pull request,
+// following the GraphQL cursor until there are no more pages or the page cap
+// is reached, bypassing the thread cache. Comments come without their diff
+// hunks.
+func (s *githubService) fetchReviewThreads(ctx context.Context, owner, repo string, number int) ([]reviewThread, threadFetchStats, error) {
+	return s.queryReviewThreads(ctx, owner, repo, number, false)
+}
+
+// queryReviewThreads implements fetchReviewThreads, selecting each comment's
+// diff hunk when withDiffHunk is set.
+func (s *githubService) queryReviewThreads(ctx context.Context, owner, repo string, number int, withDiffHunk bool) ([]reviewThread, threadFetchStats, error) {
 	variables := map[string]interface{}{
 		"owner":    githubv4.String(owner),
 		"repo":     githubv4.String(repo),
-		"prNumber": githubv4.Int(prNumber),
+		"prNumber": githubv4.Int(number),
+		"after":    (*githubv4.String)(nil),
+		"withHunk": githubv4.Boolean(withDiffHunk),
 	}
 
-	if err := s.graphqlClient.Query(ctx, &query, variables); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("GitHub GraphQL query failed: %v", err)), nil
+	var threads []reviewThread
+	stats := threadFetchStats{PageCap: s.maxGraphQLPages}
+	budget := &pageBudget{remaining: s.maxGraphQLPages}
+	for budget.take() {
+		var query prCommentsQuery
+		if err := s.graphqlClient.Query(ctx, &query, variables); err != nil {
+			return nil, threadFetchStats{}, err
+		}
+
+		stats.Cost += int(query.RateLimit.Cost)
+		stats.Remaining = int(query.RateLimit.Remaining)
+		slog.Debug("GraphQL review threads query",
+			"repo", owner+"/"+repo,
+			"pr", number,
+			"cost", int(query.RateLimit.Cost),
+			"remaining", int(query.RateLimit.Remaining),
+		)
+
+		page := query.Repository.PullRequest.ReviewThreads
+		threads = append(threads, page.Nodes...)
+
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = githubv4.NewString(page.PageInfo.EndCursor)
 	}
 
-	var responseBuilder strings.Builder
-	threadCount := 0
-	for _, thread := range query.Repository.PullRequest.ReviewThreads.Nodes {
-		isResolved := bool(thread.IsResolved)
+	for i := range threads {
+		if err := s.fetchRemainingComments(ctx, &threads[i], withDiffHunk, budget, &stats); err != nil {
+			return nil, threadFetchStats{}, err
+		}
+	}
 
-		if unresolvedOnly && isResolved {
-			continue
+	if budget.exhausted {
+		stats.Truncated = true
+		slog.Warn("Stopped paginating review threads at the page cap",
+			"repo", owner+"/"+repo,
+			"pr", number,
+			"max_pages", s.maxGraphQLPages,
+		)
+	}
+
+	return threads, stats, nil
+}
+
+// fetchRemainingComments loads the comments of a thread that did not fit in
+// the first page of the review threads query, appending them in order and
+// adding each query's cost to stats. It stops early, leaving the thread's page
+// info in place, once budget is spent.
+func (s *githubService) fetchRemainingComments(ctx context.Context, thread *reviewThread, withDiffHunk bool, budget *pageBudget, stats *threadFetchStats) error {
+	if !thread.Comments.PageInfo.HasNextPage {
+		return nil
+	}
+
+	variables := map[string]interface{}{
+		"threadId": thread.ID,
+		"after":    githubv4.NewString(thread.Comments.PageInfo.EndCursor),
+		"withHunk": githubv4.Boolean(withDiffHunk),
+	}
+
+	for {
+		if !budget.take() {
+			return nil
 		}
 
-		threadCount++
-		if len(thread.Comments.Nodes) > 0 {
-			firstComment := thread.Comments.Nodes[0]
-			status := "Resolved"
-			if !isResolved {
-				status = "Unresolved"
-			}
+		var query threadCommentsQuery
+		if err := s.graphqlClient.Query(ctx, &query, variables); err != nil {
+			return err
+		}
 
-			responseBuilder.WriteString(fmt.Sprintf(
-				"=== %s Thread on: %s (Line %d) ===\n",
-				status,
-				string(firstComment.Path),
-				int(firstComment.Line),
-			))
+		stats.Cost += int(query.RateLimit.Cost)
+		stats.Remaining = int(query.RateLimit.Remaining)
 
-			for i, comment := range thread.Comments.Nodes {
-				if i > 0 {
-					responseBuilder.WriteString("\n--- Reply ---\n")
-				}
-				responseBuilder.WriteString(fmt.Sprintf(
-					"@%s:\n%s\n",
-					string(comment.Author.Login),
-					string(comment.Body),
-				))
-			}
-			responseBuilder.WriteString(fmt.Sprintf("\n(Thread Link: %s)\n\n", firstComment.URL.String()))
+		page := query.Node.PullRequestReviewThread.Comments
+		thread.Comments.Nodes = append(thread.Comments.Nodes, page.Nodes...)
+
+		if !page.PageInfo.HasNextPage {
+			break
 		}
+		variables["after"] = githubv4.NewString(page.PageInfo.EndCursor)
 	}
 
-	if threadCount == 0 {
-		if unresolvedOnly {
-			return mcp.NewToolResultText("No unresolved comments found on that PR."), nil
-		} else {
-			return mcp.NewToolResultText("No comments found on that PR."), nil
-		}
+	thread.Comments.PageInfo = pageInfo{}
+	return nil
+}
+
+// githubErrorMessage extracts the human-readable message and any detailed
+// validation errors from a GitHub REST API error, falling back to the raw error.
+// Context added by wrapping the error with %w is kept in front.
+func githubErrorMessage(err error) string {
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) {
+		return err.Error()
 	}
 
-	filterText := ""
-	if unresolvedOnly {
-		filterText = " unresolved"
+	details := []string{errResp.Message}
+	for _, e := range errResp.Errors {
+		if e.Message != "" {
+			details = append(details, e.Message)
+		} else if e.Code != "" {
+			details = append(details, fmt.Sprintf("%s %s on %s", e.Code, e.Field, e.Resource))
+		}
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d%s comment threads:\n\n%s", threadCount, filterText, responseBuilder.String())), nil
+	prefix := strings.TrimSuffix(err.Error(), errResp.Error())
+	return prefix + strings.Join(details, ": ")
+}
+
+// commentAuthorLine renders the author and creation time of a comment, e.g.
+// "@alice (2024-05-01 14:03 UTC)" or "@alice (3 days ago)".
+func commentAuthorLine(comment reviewComment, relative bool) string {
+	return fmt.Sprintf("@%s (%s)", string(comment.Author.Login), formatTimestamp(comment.CreatedAt.Time, relative))
+}
+
+func formatTimestamp(t time.Time, relative bool) string {
+	if relative {
+		return humanizeSince(t, time.Now())
+	}
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// humanizeSince describes how long before now t was, using the largest
+// whole unit ("just now", "5 minutes ago", "3 days ago", "2 years ago").
+func humanizeSince(t, now time.Time) string {
+	d := now.Sub(t)
+	if d < 0 {
+		return "in the future"
+	}
+
+	if amount := humanizeDuration(d); amount != "" {
+		return amount + " ago"
+	}
+	return "just now"
+}
+
+// humanizeUntil is the forward-looking counterpart of humanizeSince
+// ("in 12 minutes", "in 1 hour").
+func humanizeUntil(t, now time.Time) string {
+	d := t.Sub(now)
+	if d <= 0 {
+		return "now"
+	}
+
+	if amount := humanizeDuration(d); amount != "" {
+		return "in " + amount
+	}
+	return "in less than a minute"
+}
+
+// humanizeDuration renders d in its largest whole unit, or returns an empty
+// string for durations under a minute.
+func humanizeDuration(d time.Duration) string {
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"week", 7 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+
+	for _, unit := range units {
+		if n := int(d / unit.size); n >= 1 {
+			if n == 1 {
+				return fmt.Sprintf("1 %s", unit.name)
+			}
+			return fmt.Sprintf("%d %ss", n, unit.name)
+		}
+	}
+
+	return ""
+}
+
+var reactionEmoji = map[githubv4.ReactionContent]string{
+	githubv4.ReactionContentThumbsUp:   "👍",
+	githubv4.ReactionContentThumbsDown: "👎",
+	githubv4.ReactionContentLaugh:      "😄",
+	githubv4.ReactionContentHooray:     "🎉",
+	githubv4.ReactionContentConfused:   "😕",
+	githubv4.ReactionContentHeart:      "❤️",
+	githubv4.ReactionContentRocket:     "🚀",
+	githubv4.ReactionContentEyes:       "👀",
+}
+
+// reactionSummary renders a comment's reactions compactly, e.g. "[👍 3, 👀 1]",
+// or returns an empty string when nobody has reacted.
+func reactionSummary(comment reviewComment) string {
+	var parts []string
+	for _, group := range comment.ReactionGroups {
+		if group.Users.TotalCount == 0 {
+			continue
+		}
+		emoji, ok := reactionEmoji[group.Content]
+		if !ok {
+			emoji = string(group.Content)
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", emoji, int(group.Users.TotalCount)))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// threadLocation describes where a thread sits in the diff. Outdated threads
+// have no current line (GraphQL reports 0), so their original line is shown
+// instead when known.
+func threadLocation(thread reviewThread) string {
+	if len(thread.Comments.Nodes) == 0 {
+		return "no comments"
+	}
+
+	firstComment := thread.Comments.Nodes[0]
+	if !thread.IsOutdated && firstComment.Line > 0 {
+		return fmt.Sprintf("Line %d", int(firstComment.Line))
+	}
+	if firstComment.OriginalLine > 0 {
+		return fmt.Sprintf("outdated, originally Line %d", int(firstComment.OriginalLine))
+	}
+	return "outdated"
+}
+
+// commentPreview shortens long comment bodies for the unresolved comments
+// listing. Bodies longer than maxChars that span more than maxLines lines are
+// cut to their first maxLines lines; the number of hidden lines is returned
+// so the caller can render a footer.
+func commentPreview(body string, maxChars, maxLines int) (string, int) {
+	if len(body) <= maxChars {
+		return body, 0
+	}
+
+	lines := strings.Split(body, "\n")
+	if len(lines) <= maxLines {
+		return body, 0
+	}
+
+	return strings.Join(lines[:maxLines], "\n"), len(lines) - maxLines
+}
+
+// getThreadsStructuredHandler returns a pull request's review threads as JSON
+// only, for automation that parses thread state rather than reading it.
+func (s *githubService) getThreadsStructuredHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	threads, stats, err := s.getReviewThreads(ctx, owner, repo, prNumber, req.GetBool("no_cache", false))
+	if err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	unresolvedOnly := req.GetBool("unresolved_only", false)
+	output := threadListOutput{Threads: []threadOutput{}, Truncated: stats.Truncated}
+	for _, thread := range threads {
+		if unresolvedOnly && bool(thread.IsResolved) {
+			continue
+		}
+		output.Threads = append(output.Threads, newThreadOutput(thread))
+	}
+	output.Total = len(output.Threads)
+
+	return jsonResult(output)
+}
+
+func (s *githubService) getUnresolvedCommentsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	relativeTime := req.GetBool("relative_time", false)
+	format, err := getFormat(req)
+	if err != nil {
+		return toolError(err.Error()), nil
+	}
+
+	maxPreviewChars := req.GetInt("max_preview_chars", 200)
+	maxPreviewLines := req.GetInt("max_preview_lines", 3)
+	if maxPreviewChars < 1 || maxPreviewLines < 1 {
+		return toolError("max_preview_chars and max_preview_lines must be positive"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	threads, stats, err := s.getReviewThreads(ctx, owner, repo, prNumber, req.GetBool("no_cache", false))
+	if err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	costHeader := ""
+	if req.GetBool("show_cost", false) {
+		costHeader = stats.costHeader()
+	}
+
+	if format == "json" {
+		output := threadListOutput{Threads: []threadOutput{}, Truncated: stats.Truncated}
+		for _, thread := range threads {
+			if !thread.IsResolved {
+				output.Threads = append(output.Threads, newThreadOutput(thread))
+			}
+		}
+		output.Total = len(output.Threads)
+		return jsonResult(output)
+	}
+
+	var responseBuilder strings.Builder
+	unresolvedCount := 0
+	for _, thread := range threads {
+		if !thread.IsResolved {
+			unresolvedCount++
+			if len(thread.Comments.Nodes) > 0 {
+				firstComment := thread.Comments.Nodes[0]
+				responseBuilder.WriteString(fmt.Sprintf(
+					"Unresolved Thread on: %s (%s)\n",
+					string(firstComment.Path),
+					threadLocation(thread),
+				))
+
+				for _, comment := range thread.Comments.Nodes {
+					preview, hiddenLines := commentPreview(string(comment.Body), maxPreviewChars, maxPreviewLines)
+					if hiddenLines > 0 {
+						responseBuilder.WriteString(fmt.Sprintf(
+							"  - %s: %s\n… +%d lines (ctrl+o to expand)\n",
+							commentAuthorLine(comment, relativeTime),
+							preview,
+							hiddenLines,
+						))
+					} else {
+						responseBuilder.WriteString(fmt.Sprintf(
+							"  - %s: %s\n",
+							commentAuthorLine(comment, relativeTime),
+							preview,
+						))
+					}
+				}
+				responseBuilder.WriteString(fmt.Sprintf("  (Thread Link: %s)\n\n", firstComment.URL.String())) // .URL is a URI
+			}
+		}
+	}
+
+	if unresolvedCount == 0 {
+		if len(threads) > 0 {
+			return mcp.NewToolResultText(costHeader + fmt.Sprintf("No unresolved comments found on that PR (all %d threads resolved).", len(threads)) + stats.truncationWarning()), nil
+		}
+		return mcp.NewToolResultText(costHeader + "No unresolved comments found on that PR." + stats.truncationWarning()), nil
+	}
+
+	return mcp.NewToolResultText(costHeader + fmt.Sprintf("Found %d unresolved of %d comment threads (%d resolved):\n\n%s", unresolvedCount, len(threads), len(threads)-unresolvedCount, responseBuilder.String()) + stats.truncationWarning()), nil
+}
+
+func (s *githubService) getFullCommentsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	unresolvedOnly := req.GetBool("unresolved_only", false)
+	relativeTime := req.GetBool("relative_time", false)
+	includeDiffHunk := req.GetBool("include_diff_hunk", false)
+	author := strings.TrimPrefix(strings.TrimSpace(req.GetString("author", "")), "@")
+	format, err := getFormat(req)
+	if err != nil {
+		return toolError(err.Error()), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	getThreads := s.getReviewThreads
+	if includeDiffHunk {
+		getThreads = s.getReviewThreadsWithDiffHunks
+	}
+	threads, stats, err := getThreads(ctx, owner, repo, prNumber, req.GetBool("no_cache", false))
+	if err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	costHeader := ""
+	if req.GetBool("show_cost", false) {
+		costHeader = stats.costHeader()
+	}
+
+	if format == "json" {
+		output := threadListOutput{Threads: []threadOutput{}, Truncated: stats.Truncated}
+		for _, thread := range threads {
+			if unresolvedOnly && bool(thread.IsResolved) {
+				continue
+			}
+			if author != "" && !threadHasAuthor(thread, author) {
+				continue
+			}
+			output.Threads = append(output.Threads, newThreadOutput(thread))
+		}
+		output.Total = len(output.Threads)
+		return jsonResult(output)
+	}
+
+	var responseBuilder strings.Builder
+	threadCount := 0
+	for _, thread := range threads {
+		isResolved := bool(thread.IsResolved)
+
+		if unresolvedOnly && isResolved {
+			continue
+		}
+		if author != "" && !threadHasAuthor(thread, author) {
+			continue
+		}
+
+		threadCount++
+		if len(thread.Comments.Nodes) > 0 {
+			firstComment := thread.Comments.Nodes[0]
+			status := "Resolved"
+			if !isResolved {
+				status = "Unresolved"
+			}
+
+			responseBuilder.WriteString(fmt.Sprintf(
+				"=== %s Thread on: %s (%s) ===\n",
+				status,
+				string(firstComment.Path),
+				threadLocation(thread),
+			))
+
+			// Replies share the diff hunk of the comment that started the
+			// thread, so it is only rendered once.
+			if includeDiffHunk && firstComment.DiffHunk != "" {
+				responseBuilder.WriteString(fmt.Sprintf("```diff\n%s\n```\n", string(firstComment.DiffHunk)))
+			}
+
+			for i, comment := range thread.Comments.Nodes {
+				if i > 0 {
+					responseBuilder.WriteString("\n--- Reply ---\n")
+				}
+				marker := ""
+				if author != "" && strings.EqualFold(string(comment.Author.Login), author) {
+					marker = "» "
+				}
+				responseBuilder.WriteString(fmt.Sprintf(
+					"%s%s:\n%s\n",
+					marker,
+					commentAuthorLine(comment, relativeTime),
+					string(comment.Body),
+				))
+				if reactions := reactionSummary(comment); reactions != "" {
+					responseBuilder.WriteString(reactions + "\n")
+				}
+				responseBuilder.WriteString(fmt.Sprintf("(Comment ID: %d)\n", int64(comment.DatabaseID)))
+			}
+			responseBuilder.WriteString(fmt.Sprintf("\n(Thread Link: %s)\n(Thread ID: %v)\n\n", firstComment.URL.String(), thread.ID))
+		}
+	}
+
+	authorText := ""
+	if author != "" {
+		authorText = fmt.Sprintf(" with comments by @%s", author)
+	}
+
+	if threadCount == 0 {
+		if unresolvedOnly {
+			return mcp.NewToolResultText(costHeader + fmt.Sprintf("No unresolved comments%s found on that PR.", authorText) + stats.truncationWarning()), nil
+		} else {
+			return mcp.NewToolResultText(costHeader + fmt.Sprintf("No comments%s found on that PR.", authorText) + stats.truncationWarning()), nil
+		}
+	}
+
+	filterText := ""
+	if unresolvedOnly {
+		filterText = " unresolved"
+	}
+
+	return mcp.NewToolResultText(costHeader + fmt.Sprintf("Found %d%s comment threads%s:\n\n%s", threadCount, filterText, authorText, responseBuilder.String()) + stats.truncationWarning()), nil
+}
+
+// reviewCommentAnchorRegex matches the fragment GitHub links a single review
+// comment with: #discussion_r456 on the conversation tab, #r456 on the files tab.
+var reviewCommentAnchorRegex = regexp.MustCompile(`#(?:discussion_)?r(\d+)$`)
+
+// parseReviewCommentURL splits a review comment link into its pull request and
+// the comment's database ID.
+func parseReviewCommentURL(url string) (owner string, repo string, number int, commentID int64, err error) {
+	owner, repo, number, err = parsePRURL(url)
+	if err != nil {
+		return "", "", 0, 0, err
+	}
+
+	matches := reviewCommentAnchorRegex.FindStringSubmatch(strings.TrimSpace(url))
+	if len(matches) != 2 {
+		return "", "", 0, 0, fmt.Errorf("no review comment in URL. Expected: .../owner/repo/pull/123#discussion_r456")
+	}
+
+	commentID, err = strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return "", "", 0, 0, fmt.Errorf("invalid comment ID: %s", matches[1])
+	}
+
+	return owner, repo, number, commentID, nil
+}
+
+func (s *githubService) getThreadByCommentURLHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	commentURL, err := req.RequireString("comment_url")
+	if err != nil {
+		return toolError("Missing required argument: comment_url"), nil
+	}
+
+	owner, repo, prNumber, commentID, err := parseReviewCommentURL(commentURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid comment URL: %v", err)), nil
+	}
+
+	threads, stats, err := s.getReviewThreadsWithDiffHunks(ctx, owner, repo, prNumber, req.GetBool("no_cache", false))
+	if err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	relativeTime := req.GetBool("relative_time", false)
+	for _, thread := range threads {
+		linked := -1
+		for i, comment := range thread.Comments.Nodes {
+			if int64(comment.DatabaseID) == commentID {
+				linked = i
+				break
+			}
+		}
+		if linked < 0 {
+			continue
+		}
+
+		firstComment := thread.Comments.Nodes[0]
+		status := "Resolved"
+		if !thread.IsResolved {
+			status = "Unresolved"
+		}
+
+		var responseBuilder strings.Builder
+		responseBuilder.WriteString(fmt.Sprintf("=== %s Thread on: %s (%s) ===\n", status, string(firstComment.Path), threadLocation(thread)))
+		if firstComment.DiffHunk != "" {
+			responseBuilder.WriteString(fmt.Sprintf("```diff\n%s\n```\n", string(firstComment.DiffHunk)))
+		}
+
+		for i, comment := range thread.Comments.Nodes {
+			if i > 0 {
+				responseBuilder.WriteString("\n--- Reply ---\n")
+			}
+			marker := ""
+			if i == linked {
+				marker = "» "
+			}
+			responseBuilder.WriteString(fmt.Sprintf("%s%s:\n%s\n", marker, commentAuthorLine(comment, relativeTime), string(comment.Body)))
+			if reactions := reactionSummary(comment); reactions != "" {
+				responseBuilder.WriteString(reactions + "\n")
+			}
+			responseBuilder.WriteString(fmt.Sprintf("(Comment ID: %d)\n", int64(comment.DatabaseID)))
+		}
+		responseBuilder.WriteString(fmt.Sprintf("\n(Thread Link: %s)\n(Thread ID: %v)\n", firstComment.URL.String(), thread.ID))
+
+		return mcp.NewToolResultText(responseBuilder.String() + stats.truncationWarning()), nil
+	}
+
+	return toolError(fmt.Sprintf("Review comment %d was not found in any of the %d review threads on %s/%s#%d. It may have been deleted, or it may not be a review comment.", commentID, len(threads), owner, repo, prNumber) + stats.truncationWarning()), nil
+}
+
+// threadHasAuthor reports whether any comment in thread was written by login,
+// compared case-insensitively as GitHub logins are.
+func threadHasAuthor(thread reviewThread, login string) bool {
+	for _, comment := range thread.Comments.Nodes {
+		if strings.EqualFold(string(comment.Author.Login), login) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *githubService) getReviewCommentsSinceHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	sinceArg, err := req.RequireString("since")
+	if err != nil {
+		return toolError("Missing required argument: since"), nil
+	}
+
+	since, err := parseSince(sinceArg)
+	if err != nil {
+		return toolError(err.Error()), nil
+	}
+
+	relativeTime := req.GetBool("relative_time", false)
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	threads, stats, err := s.getReviewThreads(ctx, owner, repo, prNumber, req.GetBool("no_cache", false))
+	if err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	var responseBuilder strings.Builder
+	threadCount, commentCount := 0, 0
+	for _, thread := range threads {
+		var newer []reviewComment
+		for _, comment := range thread.Comments.Nodes {
+			if comment.CreatedAt.After(since) {
+				newer = append(newer, comment)
+			}
+		}
+		if len(newer) == 0 {
+			continue
+		}
+		threadCount++
+		commentCount += len(newer)
+
+		status := "Resolved"
+		if !thread.IsResolved {
+			status = "Unresolved"
+		}
+		firstComment := thread.Comments.Nodes[0]
+		responseBuilder.WriteString(fmt.Sprintf(
+			"=== %s Thread on: %s (%s) ===\n",
+			status,
+			string(firstComment.Path),
+			threadLocation(thread),
+		))
+
+		if earlier := len(thread.Comments.Nodes) - len(newer); earlier > 0 {
+			responseBuilder.WriteString(fmt.Sprintf("(%d earlier comments not shown)\n", earlier))
+		}
+		for _, comment := range newer {
+			responseBuilder.WriteString(fmt.Sprintf(
+				"%s:\n%s\n",
+				commentAuthorLine(comment, relativeTime),
+				string(comment.Body),
+			))
+		}
+		responseBuilder.WriteString(fmt.Sprintf("\n(Thread Link: %s)\n(Thread ID: %v)\n\n", firstComment.URL.String(), thread.ID))
+	}
+
+	if threadCount == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No review comments on that PR since %s.", formatTimestamp(since, false)) + stats.truncationWarning()), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Found %d new comments in %d threads since %s:\n\n%s", commentCount, threadCount, formatTimestamp(since, false), responseBuilder.String()) + stats.truncationWarning()), nil
+}
+
+// maxConcurrentPRFetches bounds how many pull requests get_comments_for_prs
+// fetches at once, to stay clear of GitHub's secondary rate limits.
+const maxConcurrentPRFetches = 5
+
+// parsePRURLList accepts pull request URLs either as a JSON array of strings
+// or as a comma-separated list.
+func parsePRURLList(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") {
+		var urls []string
+		if err := json.Unmarshal([]byte(value), &urls); err != nil {
+			return nil, fmt.Errorf("invalid JSON array of URLs: %v", err)
+		}
+		return splitList(strings.Join(urls, ",")), nil
+	}
+	return splitList(value), nil
+}
+
+func (s *githubService) getCommentsForPRsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	urlsArg, err := req.RequireString("pull_request_urls")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_urls"), nil
+	}
+
+	urls, err := parsePRURLList(urlsArg)
+	if err != nil {
+		return toolError(err.Error()), nil
+	}
+	if len(urls) == 0 {
+		return toolError("Missing required argument: pull_request_urls"), nil
+	}
+
+	noCache := req.GetBool("no_cache", false)
+
+	// Each pull request gets its own section; a failure is reported in that
+	// section rather than failing the whole call.
+	sections := make([]string, len(urls))
+	var group errgroup.Group
+	group.SetLimit(maxConcurrentPRFetches)
+	for i, prURL := range urls {
+		group.Go(func() error {
+			sections[i] = s.prCommentsSection(ctx, prURL, noCache)
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	return mcp.NewToolResultText(fmt.Sprintf("Review threads for %d pull requests:\n\n%s", len(urls), strings.Join(sections, "\n"))), nil
+}
+
+// prCommentsSection renders the unresolved threads of one pull request for
+// get_comments_for_prs.
+func (s *githubService) prCommentsSection(ctx context.Context, prURL string, noCache bool) string {
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return fmt.Sprintf("=== %s ===\nError: Invalid PR URL: %v\n", prURL, err)
+	}
+
+	header := fmt.Sprintf("%s/%s#%d", owner, repo, prNumber)
+	threads, stats, err := s.getReviewThreads(ctx, owner, repo, prNumber, noCache)
+	if err != nil {
+		return fmt.Sprintf("=== %s ===\nError: GitHub GraphQL query failed: %s\n", header, redactSecrets(err.Error()))
+	}
+
+	var sectionBuilder strings.Builder
+	unresolved := 0
+	for _, thread := range threads {
+		if thread.IsResolved || len(thread.Comments.Nodes) == 0 {
+			continue
+		}
+		unresolved++
+
+		firstComment := thread.Comments.Nodes[0]
+		preview, hiddenLines := commentPreview(string(firstComment.Body), 200, 3)
+		sectionBuilder.WriteString(fmt.Sprintf("Unresolved Thread on: %s (%s)\n", string(firstComment.Path), threadLocation(thread)))
+		sectionBuilder.WriteString(fmt.Sprintf("  - %s: %s\n", commentAuthorLine(firstComment, false), preview))
+		if hiddenLines > 0 {
+			sectionBuilder.WriteString(fmt.Sprintf("… +%d lines\n", hiddenLines))
+		}
+		if replies := len(thread.Comments.Nodes) - 1; replies > 0 {
+			sectionBuilder.WriteString(fmt.Sprintf("  (%d replies)\n", replies))
+		}
+		sectionBuilder.WriteString(fmt.Sprintf("  (Thread Link: %s)\n", firstComment.URL.String()))
+	}
+
+	if unresolved == 0 {
+		return fmt.Sprintf("=== %s: no unresolved threads (%d total) ===\n", header, len(threads)) + stats.truncationWarning()
+	}
+	return fmt.Sprintf("=== %s: %d unresolved of %d threads ===\n%s", header, unresolved, len(threads), sectionBuilder.String()) + stats.truncationWarning()
+}
+
+func (s *githubService) resolveReviewThreadHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	threadID, err := req.RequireString("thread_id")
+	if err != nil {
+		return toolError("Missing required argument: thread_id"), nil
+	}
+
+	var status reviewThreadStatusQuery
+	variables := map[string]interface{}{
+		"threadId": githubv4.ID(threadID),
+	}
+
+	if err := s.graphqlClient.Query(ctx, &status, variables); err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	thread := status.Node.PullRequestReviewThread
+	path := "unknown file"
+	if len(thread.Comments.Nodes) > 0 {
+		path = string(thread.Comments.Nodes[0].Path)
+	}
+
+	if thread.IsResolved {
+		return mcp.NewToolResultText(fmt.Sprintf("Thread on %s is already resolved.", path)), nil
+	}
+
+	var mutation resolveReviewThreadMutation
+	input := githubv4.ResolveReviewThreadInput{
+		ThreadID: githubv4.ID(threadID),
+	}
+
+	if err := s.graphqlClient.Mutate(ctx, &mutation, input, nil); err != nil {
+		return apiError("Failed to resolve review thread", err)
+	}
+
+	pr := thread.PullRequest
+	s.threadCache.invalidate(threadCacheKey(string(pr.Repository.Owner.Login), string(pr.Repository.Name), int(pr.Number)))
+
+	return mcp.NewToolResultText(fmt.Sprintf("Resolved review thread on %s.", path)), nil
+}
+
+// resolveMyThreadsHandler resolves every unresolved thread on a pull request
+// that the authenticated user started. Threads are resolved one at a time and
+// each outcome is reported, so a partial failure shows exactly what changed.
+func (s *githubService) resolveMyThreadsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	login := s.login
+	if login == "" {
+		var query viewerQuery
+		if err := s.graphqlClient.Query(ctx, &query, nil); err != nil {
+			return apiError("GitHub GraphQL query failed", err)
+		}
+		login = string(query.Viewer.Login)
+	}
+
+	// Resolution state is what this tool acts on, so skip the thread cache.
+	threads, stats, err := s.fetchReviewThreads(ctx, owner, repo, prNumber)
+	if err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	var responseBuilder strings.Builder
+	resolved, failed := 0, 0
+	for _, thread := range threads {
+		if thread.IsResolved || len(thread.Comments.Nodes) == 0 {
+			continue
+		}
+
+		firstComment := thread.Comments.Nodes[0]
+		if !strings.EqualFold(string(firstComment.Author.Login), login) {
+			continue
+		}
+
+		location := fmt.Sprintf("%s (%s)", string(firstComment.Path), threadLocation(thread))
+
+		var mutation resolveReviewThreadMutation
+		input := githubv4.ResolveReviewThreadInput{ThreadID: thread.ID}
+		if err := s.graphqlClient.Mutate(ctx, &mutation, input, nil); err != nil {
+			failed++
+			responseBuilder.WriteString(fmt.Sprintf("- [failed] %s: %v\n", location, err))
+			continue
+		}
+
+		resolved++
+		responseBuilder.WriteString(fmt.Sprintf("- [resolved] %s\n", location))
+	}
+	if resolved > 0 {
+		s.threadCache.invalidate(threadCacheKey(owner, repo, prNumber))
+	}
+
+	if resolved+failed == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No unresolved threads started by @%s on that PR.", login) + stats.truncationWarning()), nil
+	}
+
+	summary := fmt.Sprintf("Resolved %d of %d unresolved threads started by @%s", resolved, resolved+failed, login)
+	if failed > 0 {
+		return toolError(fmt.Sprintf("%s; %d failed:\n\n%s", summary, failed, responseBuilder.String()) + stats.truncationWarning()), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("%s:\n\n%s", summary, responseBuilder.String()) + stats.truncationWarning()), nil
+}
+
+func (s *githubService) replyToReviewCommentHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	body := strings.TrimSpace(req.GetString("body", ""))
+	if body == "" {
+		return toolError("Reply body must not be empty"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	commentID := int64(req.GetInt("comment_id", 0))
+	threadID := req.GetString("thread_id", "")
+	if commentID == 0 && threadID == "" {
+		return toolError("Either thread_id or comment_id must be provided"), nil
+	}
+
+	if commentID == 0 {
+		var status reviewThreadStatusQuery
+		variables := map[string]interface{}{
+			"threadId": githubv4.ID(threadID),
+		}
+
+		if err := s.graphqlClient.Query(ctx, &status, variables); err != nil {
+			return apiError("GitHub GraphQL query failed", err)
+		}
+
+		comments := status.Node.PullRequestReviewThread.Comments.Nodes
+		if len(comments) == 0 {
+			return toolError(fmt.Sprintf("No comments found in thread %s", threadID)), nil
+		}
+		commentID = int64(comments[0].DatabaseID)
+	}
+
+	comment, _, err := s.restClient.PullRequests.CreateCommentInReplyTo(ctx, owner, repo, prNumber, body, commentID)
+	if err != nil {
+		return apiError("Failed to post reply", err)
+	}
+	s.threadCache.invalidate(threadCacheKey(owner, repo, prNumber))
+
+	return mcp.NewToolResultText(fmt.Sprintf("Reply posted: %s", comment.GetHTMLURL())), nil
+}
+
+func (s *githubService) editReviewCommentHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	body := strings.TrimSpace(req.GetString("body", ""))
+	if body == "" {
+		return toolError("Comment body must not be empty"), nil
+	}
+
+	commentID := int64(req.GetInt("comment_id", 0))
+	if commentID <= 0 {
+		return toolError("Missing required argument: comment_id"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	comment, _, err := s.restClient.PullRequests.EditComment(ctx, owner, repo, commentID, &github.PullRequestComment{
+		Body: github.String(body),
+	})
+	if err != nil {
+		return apiError("Failed to edit review comment", err)
+	}
+	s.threadCache.invalidate(threadCacheKey(owner, repo, prNumber))
+
+	return mcp.NewToolResultText(fmt.Sprintf("Comment updated: %s", comment.GetHTMLURL())), nil
+}
+
+func (s *githubService) deleteReviewCommentHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	commentID := int64(req.GetInt("comment_id", 0))
+	if commentID <= 0 {
+		return toolError("Missing required argument: comment_id"), nil
+	}
+
+	if !req.GetBool("confirm", false) {
+		return toolError("Deleting a review comment cannot be undone. Call again with confirm set to true to proceed."), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	resp, err := s.restClient.PullRequests.DeleteComment(ctx, owner, repo, commentID)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusForbidden {
+			return toolError(fmt.Sprintf("Permission denied: the token cannot delete comment %d, which is usually because it belongs to another user (%s)", commentID, githubErrorMessage(err))), nil
+		}
+		return apiError("Failed to delete review comment", err)
+	}
+	s.threadCache.invalidate(threadCacheKey(owner, repo, prNumber))
+
+	return mcp.NewToolResultText(fmt.Sprintf("Deleted review comment %d from %s/%s.", commentID, owner, repo)), nil
+}
+
+func (s *githubService) approvePullRequestHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	review := &github.PullRequestReviewRequest{
+		Event: github.String("APPROVE"),
+	}
+	if body := req.GetString("body", ""); body != "" {
+		review.Body = github.String(body)
+	}
+
+	result, _, err := s.restClient.PullRequests.CreateReview(ctx, owner, repo, prNumber, review)
+	if err != nil {
+		message := githubErrorMessage(err)
+		if strings.Contains(strings.ToLower(message), "own pull request") {
+			return toolError(fmt.Sprintf("You cannot approve your own pull request (%s)", message)), nil
+		}
+		return apiError("Failed to approve pull request", err)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Review submitted (state: %s)\n%s", result.GetState(), result.GetHTMLURL())), nil
+}
+
+func (s *githubService) requestChangesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	body := strings.TrimSpace(req.GetString("body", ""))
+	if body == "" {
+		return toolError("A body explaining the requested changes is required"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	review := &github.PullRequestReviewRequest{
+		Event: github.String("REQUEST_CHANGES"),
+		Body:  github.String(body),
+	}
+
+	result, _, err := s.restClient.PullRequests.CreateReview(ctx, owner, repo, prNumber, review)
+	if err != nil {
+		return apiError("Failed to request changes", err)
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Review submitted (state: %s)\n%s\n", result.GetState(), result.GetHTMLURL()))
+
+	// The mergeable state is computed asynchronously by GitHub, so it is only
+	// reported when it is already known.
+	if pr, _, err := s.restClient.PullRequests.Get(ctx, owner, repo, prNumber); err == nil && pr.GetMergeableState() != "" {
+		responseBuilder.WriteString(fmt.Sprintf("Mergeable state: %s\n", pr.GetMergeableState()))
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func (s *githubService) requestReviewersHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	reviewers := github.ReviewersRequest{
+		Reviewers:     splitList(strings.ReplaceAll(req.GetString("reviewers", ""), "@", "")),
+		TeamReviewers: splitList(req.GetString("team_reviewers", "")),
+	}
+	if len(reviewers.Reviewers) == 0 && len(reviewers.TeamReviewers) == 0 {
+		return toolError("Provide at least one login in reviewers or one team slug in team_reviewers"), nil
+	}
+
+	pr, resp, err := s.restClient.PullRequests.RequestReviewers(ctx, owner, repo, prNumber, reviewers)
+	if err != nil {
+		message := githubErrorMessage(err)
+		if resp != nil && resp.StatusCode == http.StatusUnprocessableEntity && strings.Contains(strings.ToLower(message), "collaborator") {
+			return toolError(fmt.Sprintf("Reviews can only be requested from collaborators on %s/%s. Check that every requested user has access to the repository (%s)", owner, repo, message)), nil
+		}
+		return apiError("Failed to request reviewers", err)
+	}
+
+	requested := pendingReviewers(owner, pr)
+	if len(requested) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Review requested, but PR #%d has no pending reviewers (requested users may already have reviewed).\n%s", prNumber, pr.GetHTMLURL())), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Requested reviewers on PR #%d: %s\n%s", prNumber, strings.Join(requested, ", "), pr.GetHTMLURL())), nil
+}
+
+// pendingReviewers lists the users (as @login) and teams (as owner/slug) a
+// pull request is still waiting on.
+func pendingReviewers(owner string, pr *github.PullRequest) []string {
+	var requested []string
+	for _, user := range pr.RequestedReviewers {
+		requested = append(requested, "@"+user.GetLogin())
+	}
+	for _, team := range pr.RequestedTeams {
+		requested = append(requested, fmt.Sprintf("%s/%s", owner, team.GetSlug()))
+	}
+	return requested
+}
+
+// reRequestReviewHandler asks a reviewer to look at a pull request again.
+// Requesting a reviewer who already has a pending request does not notify
+// them, so a pending request is removed first and then added back.
+func (s *githubService) reRequestReviewHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	reviewer := strings.TrimPrefix(strings.TrimSpace(req.GetString("reviewer", "")), "@")
+	if reviewer == "" {
+		return toolError("Missing required argument: reviewer"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	pr, _, err := s.restClient.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return apiError("Failed to get pull request", err)
+	}
+
+	wasPending := false
+	for _, user := range pr.RequestedReviewers {
+		if strings.EqualFold(user.GetLogin(), reviewer) {
+			wasPending = true
+			break
+		}
+	}
+
+	reviewers := github.ReviewersRequest{Reviewers: []string{reviewer}}
+	if wasPending {
+		if _, err := s.restClient.PullRequests.RemoveReviewers(ctx, owner, repo, prNumber, reviewers); err != nil {
+			return apiError("Failed to remove the pending review request", err)
+		}
+	}
+
+	pr, resp, err := s.restClient.PullRequests.RequestReviewers(ctx, owner, repo, prNumber, reviewers)
+	if err != nil {
+		message := githubErrorMessage(err)
+		if resp != nil && resp.StatusCode == http.StatusUnprocessableEntity && strings.Contains(strings.ToLower(message), "collaborator") {
+			return toolError(fmt.Sprintf("Reviews can only be requested from collaborators on %s/%s. Check that @%s has access to the repository (%s)", owner, repo, reviewer, message)), nil
+		}
+		if wasPending {
+			return toolError(fmt.Sprintf("Removed the pending review request for @%s, but failed to request it again: %s", reviewer, message)), nil
+		}
+		return apiError("Failed to request review", err)
+	}
+
+	var responseBuilder strings.Builder
+	if wasPending {
+		responseBuilder.WriteString(fmt.Sprintf("@%s already had a pending review request on PR #%d; it was removed and re-added to notify them again.\n", reviewer, prNumber))
+	} else {
+		responseBuilder.WriteString(fmt.Sprintf("Re-requested review from @%s on PR #%d.\n", reviewer, prNumber))
+	}
+
+	if requested := pendingReviewers(owner, pr); len(requested) > 0 {
+		responseBuilder.WriteString(fmt.Sprintf("Pending reviewers: %s\n", strings.Join(requested, ", ")))
+	} else {
+		responseBuilder.WriteString("No pending reviewers.\n")
+	}
+	responseBuilder.WriteString(pr.GetHTMLURL())
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func (s *githubService) dismissReviewHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	reviewID := int64(req.GetInt("review_id", 0))
+	if reviewID <= 0 {
+		return toolError("Missing required argument: review_id"), nil
+	}
+
+	message := strings.TrimSpace(req.GetString("message", ""))
+	if message == "" {
+		return toolError("A message explaining the dismissal is required"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	review, resp, err := s.restClient.PullRequests.DismissReview(ctx, owner, repo, prNumber, reviewID, &github.PullRequestReviewDismissalRequest{
+		Message: github.String(message),
+	})
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound) {
+			return toolError(fmt.Sprintf("Permission denied: dismissing reviews on %s/%s requires write access, or maintainer rights when branch protection restricts dismissals (%s)", owner, repo, githubErrorMessage(err))), nil
+		}
+		return apiError("Failed to dismiss review", err)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Review %d by @%s is now %s\n%s", review.GetID(), review.GetUser().GetLogin(), review.GetState(), review.GetHTMLURL())), nil
+}
+
+func (s *githubService) mergePullRequestHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	mergeMethod := req.GetString("merge_method", "merge")
+	if mergeMethod != "merge" && mergeMethod != "squash" && mergeMethod != "rebase" {
+		return toolError(fmt.Sprintf("Invalid merge_method %q. Expected merge, squash, or rebase", mergeMethod)), nil
+	}
+
+	opts := &github.PullRequestOptions{
+		CommitTitle: req.GetString("commit_title", ""),
+		MergeMethod: mergeMethod,
+	}
+
+	result, resp, err := s.restClient.PullRequests.Merge(ctx, owner, repo, prNumber, req.GetString("commit_message", ""), opts)
+	if err != nil {
+		return s.mergeError(ctx, owner, repo, prNumber, resp, err)
+	}
+
+	if !result.GetMerged() {
+		return toolError(fmt.Sprintf("Pull request was not merged: %s", result.GetMessage())), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Pull request merged using %s.\nMerge commit SHA: %s", mergeMethod, result.GetSHA())), nil
+}
+
+// mergeError turns a failed merge into a readable explanation. GitHub
+// reports most merge blockers as a bare 405, so the pull request's mergeable
+// state is consulted to tell conflicts apart from failing requirements. Other
+// failures go through apiError.
+func (s *githubService) mergeError(ctx context.Context, owner, repo string, number int, resp *github.Response, err error) (*mcp.CallToolResult, error) {
+	if resp == nil {
+		return apiError("Failed to merge pull request", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusConflict:
+		return toolError("Merge failed: the head branch was modified while merging. Refresh and try again."), nil
+	case http.StatusMethodNotAllowed:
+		message := githubErrorMessage(err)
+		if strings.Contains(strings.ToLower(message), "status check") {
+			return toolError(fmt.Sprintf("Merge blocked: required status checks have not passed (%s)", message)), nil
+		}
+
+		pr, _, prErr := s.restClient.PullRequests.Get(ctx, owner, repo, number)
+		if prErr == nil {
+			switch pr.GetMergeableState() {
+			case "dirty":
+				return toolError("Merge blocked: the pull request has merge conflicts with the base branch. Resolve them and try again."), nil
+			case "blocked":
+				return toolError("Merge blocked: required checks or reviews are not satisfied."), nil
+			case "behind":
+				return toolError("Merge blocked: the head branch is behind the base branch and must be updated first."), nil
+			}
+		}
+		return toolError(fmt.Sprintf("Pull request is not mergeable: %s", message)), nil
+	}
+
+	return apiError("Failed to merge pull request", err)
+}
+
+func (s *githubService) createPullRequestHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	title := strings.TrimSpace(req.GetString("title", ""))
+	if title == "" {
+		return toolError("Pull request title must not be empty"), nil
+	}
+
+	head := strings.TrimSpace(req.GetString("head", ""))
+	base := strings.TrimSpace(req.GetString("base", ""))
+	if head == "" || base == "" {
+		return toolError("Both head and base branches are required"), nil
+	}
+
+	newPR := &github.NewPullRequest{
+		Title: github.String(title),
+		Head:  github.String(head),
+		Base:  github.String(base),
+		Draft: github.Bool(req.GetBool("draft", false)),
+	}
+	if body := req.GetString("body", ""); body != "" {
+		newPR.Body = github.String(body)
+	}
+
+	pr, _, err := s.restClient.PullRequests.Create(ctx, owner, repo, newPR)
+	if err != nil {
+		message := githubErrorMessage(err)
+		if strings.Contains(strings.ToLower(message), "no commits between") {
+			return toolError(fmt.Sprintf("There are no commits between %s and %s, so there is nothing to open a pull request for. Push commits to %s first (%s)", base, head, head, message)), nil
+		}
+		return apiError("Failed to create pull request", err)
+	}
+
+	kind := "pull request"
+	if pr.GetDraft() {
+		kind = "draft pull request"
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Created %s #%d: %s\n%s", kind, pr.GetNumber(), pr.GetTitle(), pr.GetHTMLURL())), nil
+}
+
+func (s *githubService) closePullRequestHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.setPullRequestState(ctx, req, "closed")
+}
+
+func (s *githubService) reopenPullRequestHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.setPullRequestState(ctx, req, "open")
+}
+
+func (s *githubService) setPullRequestState(ctx context.Context, req mcp.CallToolRequest, state string) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	// GitHub answers edits to merged pull requests with a bare 422, so check
+	// up front and explain instead.
+	current, _, err := s.restClient.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return apiError("Failed to get pull request", err)
+	}
+	if current.GetMerged() {
+		return toolError(fmt.Sprintf("PR #%d is already merged and cannot be closed or reopened\n%s", prNumber, current.GetHTMLURL())), nil
+	}
+	if current.GetState() == state {
+		return mcp.NewToolResultText(fmt.Sprintf("PR #%d is already %s\n%s", prNumber, state, current.GetHTMLURL())), nil
+	}
+
+	pr, _, err := s.restClient.PullRequests.Edit(ctx, owner, repo, prNumber, &github.PullRequest{
+		State: github.String(state),
+	})
+	if err != nil {
+		return apiError("Failed to update pull request", err)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("PR #%d is now %s\n%s", pr.GetNumber(), pr.GetState(), pr.GetHTMLURL())), nil
+}
+
+func (s *githubService) convertToDraftHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.setDraftState(ctx, req, true)
+}
+
+func (s *githubService) readyForReviewHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.setDraftState(ctx, req, false)
+}
+
+// setDraftState converts a pull request to a draft or marks it ready for
+// review. Only the GraphQL API supports both directions.
+func (s *githubService) setDraftState(ctx context.Context, req mcp.CallToolRequest, draft bool) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	var query pullRequestNodeQuery
+	variables := map[string]interface{}{
+		"owner":    githubv4.String(owner),
+		"repo":     githubv4.String(repo),
+		"prNumber": githubv4.Int(prNumber),
+	}
+	if err := s.graphqlClient.Query(ctx, &query, variables); err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	pr := query.Repository.PullRequest
+	if bool(pr.IsDraft) == draft {
+		state := "ready for review"
+		if draft {
+			state = "a draft"
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("PR #%d is already %s.", prNumber, state)), nil
+	}
+
+	var isDraft bool
+	if draft {
+		var mutation convertPullRequestToDraftMutation
+		input := githubv4.ConvertPullRequestToDraftInput{PullRequestID: pr.ID}
+		if err := s.graphqlClient.Mutate(ctx, &mutation, input, nil); err != nil {
+			return apiError("Failed to convert pull request to draft", err)
+		}
+		isDraft = bool(mutation.ConvertPullRequestToDraft.PullRequest.IsDraft)
+	} else {
+		var mutation markPullRequestReadyForReviewMutation
+		input := githubv4.MarkPullRequestReadyForReviewInput{PullRequestID: pr.ID}
+		if err := s.graphqlClient.Mutate(ctx, &mutation, input, nil); err != nil {
+			return apiError("Failed to mark pull request ready for review", err)
+		}
+		isDraft = bool(mutation.MarkPullRequestReadyForReview.PullRequest.IsDraft)
+	}
+
+	if isDraft {
+		return mcp.NewToolResultText(fmt.Sprintf("PR #%d is now a draft.", prNumber)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("PR #%d is now ready for review.", prNumber)), nil
+}
+
+// truncateAtLine shortens text to at most maxBytes, cutting at the last
+// complete line so the model never sees half a diff line.
+func truncateAtLine(text string, maxBytes int) string {
+	if len(text) <= maxBytes {
+		return text
+	}
+
+	truncated := text[:maxBytes]
+	if idx := strings.LastIndex(truncated, "\n"); idx > 0 {
+		truncated = truncated[:idx+1]
+	}
+	return truncated
+}
+
+func (s *githubService) getPRDiffHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	maxBytes := req.GetInt("max_bytes", 0)
+	if maxBytes < 0 {
+		return toolError("max_bytes must not be negative"), nil
+	}
+
+	diff, _, err := s.restClient.PullRequests.GetRaw(ctx, owner, repo, prNumber, github.RawOptions{Type: github.Diff})
+	if err != nil {
+		return apiError("Failed to fetch diff", err)
+	}
+
+	if diff == "" {
+		return mcp.NewToolResultText("The pull request has no changes."), nil
+	}
+
+	if maxBytes > 0 && len(diff) > maxBytes {
+		truncated := truncateAtLine(diff, maxBytes)
+		return mcp.NewToolResultText(fmt.Sprintf(
+			"%s\n… diff truncated: showing %d of %d bytes (increase max_bytes to see more)\n",
+			truncated,
+			len(truncated),
+			len(diff),
+		)), nil
+	}
+
+	return mcp.NewToolResultText(diff), nil
+}
+
+func (s *githubService) getPRFilesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	includePatch := req.GetBool("include_patch", false)
+
+	var files []*github.CommitFile
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := s.restClient.PullRequests.ListFiles(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return apiError("Failed to list PR files", err)
+		}
+		files = append(files, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(files) == 0 {
+		return mcp.NewToolResultText("No files changed in that PR."), nil
+	}
+
+	var responseBuilder strings.Builder
+	totalAdditions, totalDeletions := 0, 0
+	for _, file := range files {
+		totalAdditions += file.GetAdditions()
+		totalDeletions += file.GetDeletions()
+
+		name := file.GetFilename()
+		if file.GetStatus() == "renamed" && file.GetPreviousFilename() != "" {
+			name = fmt.Sprintf("%s → %s", file.GetPreviousFilename(), file.GetFilename())
+		}
+
+		responseBuilder.WriteString(fmt.Sprintf("- [%s] %s (+%d -%d, %d changes)\n",
+			file.GetStatus(),
+			name,
+			file.GetAdditions(),
+			file.GetDeletions(),
+			file.GetChanges(),
+		))
+
+		if includePatch && file.GetPatch() != "" {
+			responseBuilder.WriteString(fmt.Sprintf("```diff\n%s\n```\n", file.GetPatch()))
+		}
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Found %d changed files (+%d -%d):\n\n%s", len(files), totalAdditions, totalDeletions, responseBuilder.String())), nil
+}
+
+func (s *githubService) getPRCommitsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	var commits []*github.RepositoryCommit
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := s.restClient.PullRequests.ListCommits(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return apiError("Failed to list PR commits", err)
+		}
+		commits = append(commits, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(commits) == 0 {
+		return mcp.NewToolResultText("No commits found on that PR."), nil
+	}
+
+	var responseBuilder strings.Builder
+	for _, commit := range commits {
+		author := commit.GetAuthor().GetLogin()
+		if author == "" {
+			author = commit.GetCommit().GetAuthor().GetName()
+		}
+
+		message, _, _ := strings.Cut(commit.GetCommit().GetMessage(), "\n")
+
+		responseBuilder.WriteString(fmt.Sprintf("- %s @%s (%s): %s\n",
+			shortSHA(commit.GetSHA()),
+			author,
+			commit.GetCommit().GetAuthor().GetDate().UTC().Format("2006-01-02 15:04 UTC"),
+			message,
+		))
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Found %d commits:\n\n%s", len(commits), responseBuilder.String())), nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+type checkResult struct {
+	Name       string
+	Conclusion string
+}
+
+// fetchChecks collects both check runs (GitHub Actions and apps) and legacy
+// commit statuses for a ref, normalising every result to one of
+// success, failure, neutral, or pending.
+func (s *githubService) fetchChecks(ctx context.Context, owner, repo, ref string) ([]checkResult, error) {
+	var checks []checkResult
+
+	opts := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		result, resp, err := s.restClient.Checks.ListCheckRunsForRef(ctx, owner, repo, ref, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list check runs: %w", err)
+		}
+
+		for _, run := range result.CheckRuns {
+			conclusion := "pending"
+			if run.GetStatus() == "completed" {
+				switch run.GetConclusion() {
+				case "success":
+					conclusion = "success"
+				case "neutral", "skipped":
+					conclusion = "neutral"
+				default:
+					conclusion = "failure"
+				}
+			}
+			checks = append(checks, checkResult{Name: run.GetName(), Conclusion: conclusion})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	statusOpts := &github.ListOptions{PerPage: 100}
+	for {
+		combined, resp, err := s.restClient.Repositories.GetCombinedStatus(ctx, owner, repo, ref, statusOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get combined status: %w", err)
+		}
+
+		for _, status := range combined.Statuses {
+			conclusion := status.GetState()
+			if conclusion == "error" {
+				conclusion = "failure"
+			}
+			checks = append(checks, checkResult{Name: status.GetContext(), Conclusion: conclusion})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		statusOpts.Page = resp.NextPage
+	}
+
+	return checks, nil
+}
+
+func summarizeChecks(checks []checkResult) string {
+	counts := map[string]int{}
+	for _, check := range checks {
+		counts[check.Conclusion]++
+	}
+
+	summary := fmt.Sprintf("%d passing, %d failing, %d pending", counts["success"], counts["failure"], counts["pending"])
+	if counts["neutral"] > 0 {
+		summary += fmt.Sprintf(", %d neutral", counts["neutral"])
+	}
+	return summary
+}
+
+func (s *githubService) getPRStatusHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	pr, _, err := s.restClient.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return apiError("Failed to get pull request", err)
+	}
+
+	headSHA := pr.GetHead().GetSHA()
+	checks, err := s.fetchChecks(ctx, owner, repo, headSHA)
+	if err != nil {
+		return apiError("Failed to get checks", err)
+	}
+
+	if len(checks) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No checks are configured for commit %s.", shortSHA(headSHA))), nil
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Checks for commit %s: %s\n\n", shortSHA(headSHA), summarizeChecks(checks)))
+	for _, check := range checks {
+		responseBuilder.WriteString(fmt.Sprintf("- [%s] %s\n", check.Conclusion, check.Name))
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func (s *githubService) getPRDetailsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	pr, _, err := s.restClient.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return apiError("Failed to get pull request", err)
+	}
+
+	state := pr.GetState()
+	if pr.GetMerged() {
+		state = "merged"
+	}
+
+	var labels []string
+	for _, label := range pr.Labels {
+		labels = append(labels, label.GetName())
+	}
+
+	var assignees []string
+	for _, assignee := range pr.Assignees {
+		assignees = append(assignees, "@"+assignee.GetLogin())
+	}
+
+	var reviewers []string
+	for _, reviewer := range pr.RequestedReviewers {
+		reviewers = append(reviewers, "@"+reviewer.GetLogin())
+	}
+	for _, team := range pr.RequestedTeams {
+		reviewers = append(reviewers, fmt.Sprintf("%s/%s", owner, team.GetSlug()))
+	}
+
+	listOrNone := func(items []string) string {
+		if len(items) == 0 {
+			return "none"
+		}
+		return strings.Join(items, ", ")
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("=== PR #%d: %s ===\n", pr.GetNumber(), pr.GetTitle()))
+	responseBuilder.WriteString(fmt.Sprintf("Author: @%s (%s)\n", pr.GetUser().GetLogin(), formatTimestamp(pr.GetCreatedAt().Time, false)))
+	responseBuilder.WriteString(fmt.Sprintf("State: %s\n", state))
+	responseBuilder.WriteString(fmt.Sprintf("Draft: %t\n", pr.GetDraft()))
+	responseBuilder.WriteString(fmt.Sprintf("Branches: %s -> %s\n", pr.GetHead().GetLabel(), pr.GetBase().GetRef()))
+
+	// The mergeable state is computed asynchronously and may not be known yet.
+	if mergeable := pr.GetMergeableState(); mergeable != "" {
+		responseBuilder.WriteString(fmt.Sprintf("Mergeable state: %s\n", mergeable))
+	} else {
+		responseBuilder.WriteString("Mergeable state: not yet computed\n")
+	}
+
+	responseBuilder.WriteString(fmt.Sprintf("Labels: %s\n", listOrNone(labels)))
+	responseBuilder.WriteString(fmt.Sprintf("Assignees: %s\n", listOrNone(assignees)))
+	responseBuilder.WriteString(fmt.Sprintf("Requested reviewers: %s\n", listOrNone(reviewers)))
+	if milestone := pr.GetMilestone(); milestone != nil {
+		responseBuilder.WriteString(fmt.Sprintf("Milestone: %s (%s)\n", milestone.GetTitle(), milestone.GetState()))
+	} else {
+		responseBuilder.WriteString("Milestone: none\n")
+	}
+	responseBuilder.WriteString(fmt.Sprintf("URL: %s\n\n", pr.GetHTMLURL()))
+
+	if body := pr.GetBody(); body != "" {
+		responseBuilder.WriteString(fmt.Sprintf("%s\n", body))
+	} else {
+		responseBuilder.WriteString("(no description)\n")
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+// getPROverviewHandler combines the pull request's metadata, CI status and
+// review threads into one report, fetching the three concurrently.
+func (s *githubService) getPROverviewHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	var (
+		pr      *github.PullRequest
+		checks  []checkResult
+		threads []reviewThread
+		stats   threadFetchStats
+	)
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		var err error
+		pr, _, err = s.restClient.PullRequests.Get(groupCtx, owner, repo, prNumber)
+		if err != nil {
+			return fmt.Errorf("failed to get pull request: %w", err)
+		}
+		return nil
+	})
+	group.Go(func() error {
+		// The pull ref tracks the head commit, so the checks can be fetched
+		// without waiting for the pull request's head SHA.
+		var err error
+		checks, err = s.fetchChecks(groupCtx, owner, repo, fmt.Sprintf("pull/%d/head", prNumber))
+		return err
+	})
+	group.Go(func() error {
+		var err error
+		threads, stats, err = s.getReviewThreads(groupCtx, owner, repo, prNumber, req.GetBool("no_cache", false))
+		if err != nil {
+			return fmt.Errorf("GitHub GraphQL query failed: %w", err)
+		}
+		return nil
+	})
+	if err := group.Wait(); err != nil {
+		return apiError("Failed to build PR overview", err)
+	}
+
+	unresolved := 0
+	for _, thread := range threads {
+		if !thread.IsResolved {
+			unresolved++
+		}
+	}
+
+	state := pr.GetState()
+	switch {
+	case pr.GetMerged():
+		state = "merged"
+	case pr.GetDraft():
+		state += " (draft)"
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("=== PR #%d: %s ===\n", pr.GetNumber(), pr.GetTitle()))
+	responseBuilder.WriteString(fmt.Sprintf("Author: @%s\n", pr.GetUser().GetLogin()))
+	responseBuilder.WriteString(fmt.Sprintf("State: %s\n", state))
+	responseBuilder.WriteString(fmt.Sprintf("Branches: %s -> %s\n", pr.GetHead().GetLabel(), pr.GetBase().GetRef()))
+	responseBuilder.WriteString(fmt.Sprintf("Changes: %d files, +%d -%d in %d commits\n", pr.GetChangedFiles(), pr.GetAdditions(), pr.GetDeletions(), pr.GetCommits()))
+
+	// The mergeable state is computed asynchronously and may not be known yet.
+	if mergeable := pr.GetMergeableState(); mergeable != "" {
+		responseBuilder.WriteString(fmt.Sprintf("Mergeable state: %s\n", mergeable))
+	} else {
+		responseBuilder.WriteString("Mergeable state: not yet computed\n")
+	}
+
+	if len(checks) == 0 {
+		responseBuilder.WriteString("Checks: none configured\n")
+	} else {
+		responseBuilder.WriteString(fmt.Sprintf("Checks: %s\n", summarizeChecks(checks)))
+	}
+
+	responseBuilder.WriteString(fmt.Sprintf("Review threads: %d total, %d unresolved\n", len(threads), unresolved))
+	responseBuilder.WriteString(fmt.Sprintf("URL: %s\n", pr.GetHTMLURL()))
+
+	return mcp.NewToolResultText(responseBuilder.String() + stats.truncationWarning()), nil
+}
+
+func (s *githubService) getRateLimitHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	now := time.Now()
+
+	limits, _, err := s.restClient.RateLimit.Get(ctx)
+	if err != nil {
+		return apiError("Failed to get REST rate limits", err)
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString("REST API:\n")
+	for _, resource := range []struct {
+		name string
+		rate *github.Rate
+	}{
+		{"core", limits.GetCore()},
+		{"search", limits.GetSearch()},
+	} {
+		if resource.rate == nil {
+			continue
+		}
+		responseBuilder.WriteString(fmt.Sprintf("  - %s: %d/%d remaining, resets %s (%s)\n",
+			resource.name,
+			resource.rate.Remaining,
+			resource.rate.Limit,
+			formatTimestamp(resource.rate.Reset.Time, false),
+			humanizeUntil(resource.rate.Reset.Time, now),
+		))
+	}
+
+	var query rateLimitQuery
+	if err := s.graphqlClient.Query(ctx, &query, nil); err != nil {
+		responseBuilder.WriteString(fmt.Sprintf("\nGraphQL API:\n  unavailable: %s\n", redactSecrets(err.Error())))
+		return mcp.NewToolResultText(responseBuilder.String()), nil
+	}
+
+	rate := query.RateLimit
+	responseBuilder.WriteString(fmt.Sprintf("\nGraphQL API:\n  - %d/%d points remaining, resets %s (%s)\n",
+		int(rate.Remaining),
+		int(rate.Limit),
+		formatTimestamp(rate.ResetAt.Time, false),
+		humanizeUntil(rate.ResetAt.Time, now),
+	))
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func (s *githubService) getReviewSummaryHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	var reviews []*github.PullRequestReview
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := s.restClient.PullRequests.ListReviews(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return apiError("Failed to list reviews", err)
+		}
+		reviews = append(reviews, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	threads, stats, err := s.fetchReviewThreads(ctx, owner, repo, prNumber)
+	if err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	unresolvedCount := 0
+	for _, thread := range threads {
+		if !thread.IsResolved {
+			unresolvedCount++
+		}
+	}
+
+	// Reviews are returned oldest first. A plain comment does not replace an
+	// earlier approval or change request, mirroring how GitHub computes the
+	// review decision.
+	latestStates := map[string]string{}
+	latestIDs := map[string]int64{}
+	for _, review := range reviews {
+		reviewer := review.GetUser().GetLogin()
+		state := review.GetState()
+		if state == "PENDING" {
+			continue
+		}
+		if state == "COMMENTED" && latestStates[reviewer] != "" {
+			continue
+		}
+		latestStates[reviewer] = state
+		latestIDs[reviewer] = review.GetID()
+	}
+
+	reviewers := make([]string, 0, len(latestStates))
+	counts := map[string]int{}
+	for reviewer, state := range latestStates {
+		reviewers = append(reviewers, reviewer)
+		counts[state]++
+	}
+	sort.Strings(reviewers)
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("%d approvals, %d changes requested, %d unresolved threads\n\n",
+		counts["APPROVED"],
+		counts["CHANGES_REQUESTED"],
+		unresolvedCount,
+	))
+
+	if len(reviewers) == 0 {
+		responseBuilder.WriteString("No reviews submitted yet.\n")
+	}
+	for _, reviewer := range reviewers {
+		responseBuilder.WriteString(fmt.Sprintf("- @%s: %s (review ID %d)\n", reviewer, latestStates[reviewer], latestIDs[reviewer]))
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String() + stats.truncationWarning()), nil
+}
+
+func (s *githubService) createReviewCommentHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	path, err := req.RequireString("path")
+	if err != nil || path == "" {
+		return toolError("Missing required argument: path"), nil
+	}
+
+	line := req.GetInt("line", 0)
+	if line < 1 {
+		return toolError("line must be a positive line number"), nil
+	}
+
+	side := req.GetString("side", "RIGHT")
+	if side != "LEFT" && side != "RIGHT" {
+		return toolError(fmt.Sprintf("Invalid side %q. Expected LEFT or RIGHT", side)), nil
+	}
+
+	body := strings.TrimSpace(req.GetString("body", ""))
+	if body == "" {
+		return toolError("Comment body must not be empty"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	pr, _, err := s.restClient.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return apiError("Failed to get pull request", err)
+	}
+
+	comment, _, err := s.restClient.PullRequests.CreateComment(ctx, owner, repo, prNumber, &github.PullRequestComment{
+		Body:     github.String(body),
+		Path:     github.String(path),
+		Line:     github.Int(line),
+		Side:     github.String(side),
+		CommitID: github.String(pr.GetHead().GetSHA()),
+	})
+	if err != nil {
+		return apiError("Failed to create review comment", err)
+	}
+	s.threadCache.invalidate(threadCacheKey(owner, repo, prNumber))
+
+	return mcp.NewToolResultText(fmt.Sprintf("Review comment created on %s (Line %d): %s", path, line, comment.GetHTMLURL())), nil
+}
+
+// pendingReviewID returns the ID of the authenticated user's pending review on
+// a pull request, or 0 if there is none. GitHub only shows pending reviews to
+// their author, so any pending review listed is the user's own.
+func (s *githubService) pendingReviewID(ctx context.Context, owner, repo string, number int) (int64, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		reviews, resp, err := s.restClient.PullRequests.ListReviews(ctx, owner, repo, number, opts)
+		if err != nil {
+			return 0, err
+		}
+		for _, review := range reviews {
+			if review.GetState() == "PENDING" {
+				return review.GetID(), nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return 0, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (s *githubService) startReviewHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	// GitHub allows one pending review per user and pull request, and a
+	// review started in the web UI would make submit_review fail later.
+	pendingID, err := s.pendingReviewID(ctx, owner, repo, prNumber)
+	if err != nil {
+		return apiError("Failed to list reviews", err)
+	}
+	if pendingID != 0 {
+		return toolError(fmt.Sprintf("You already have a pending review (review ID %d) on PR #%d on GitHub. Submit or discard it on GitHub before starting a new one here.", pendingID, prNumber)), nil
+	}
+
+	pr, _, err := s.restClient.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return apiError("Failed to get pull request", err)
+	}
+
+	key := reviewBatchKey(owner, repo, prNumber)
+	existing, started := s.reviewBatches.start(key, pr.GetHead().GetSHA(), req.GetBool("replace", false))
+	if !started {
+		return toolError(fmt.Sprintf("A draft review with %d comments is already in progress on PR #%d. Add to it with add_review_comment_to_batch, post it with submit_review, or call start_review with replace set to true to discard it.", len(existing.comments), prNumber)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Started a draft review of PR #%d at commit %s. Add comments with add_review_comment_to_batch, then post them together with submit_review.", prNumber, shortSHA(pr.GetHead().GetSHA()))), nil
+}
+
+func (s *githubService) addReviewCommentToBatchHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	path, err := req.RequireString("path")
+	if err != nil || path == "" {
+		return toolError("Missing required argument: path"), nil
+	}
+
+	line := req.GetInt("line", 0)
+	if line < 1 {
+		return toolError("line must be a positive line number"), nil
+	}
+
+	side := req.GetString("side", "RIGHT")
+	if side != "LEFT" && side != "RIGHT" {
+		return toolError(fmt.Sprintf("Invalid side %q. Expected LEFT or RIGHT", side)), nil
+	}
+
+	body := strings.TrimSpace(req.GetString("body", ""))
+	if body == "" {
+		return toolError("Comment body must not be empty"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	count, ok := s.reviewBatches.add(reviewBatchKey(owner, repo, prNumber), &github.DraftReviewComment{
+		Path: github.String(path),
+		Line: github.Int(line),
+		Side: github.String(side),
+		Body: github.String(body),
+	})
+	if !ok {
+		return toolError(fmt.Sprintf("No draft review is in progress on PR #%d. Call start_review first.", prNumber)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Added a comment on %s (Line %d) to the draft review of PR #%d (%d comments so far).", path, line, prNumber, count)), nil
+}
+
+func (s *githubService) submitReviewHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	event := req.GetString("event", "COMMENT")
+	if event != "COMMENT" && event != "APPROVE" && event != "REQUEST_CHANGES" {
+		return toolError(fmt.Sprintf("Invalid event %q. Expected COMMENT, APPROVE, or REQUEST_CHANGES", event)), nil
+	}
+
+	body := strings.TrimSpace(req.GetString("body", ""))
+	if event == "REQUEST_CHANGES" && body == "" {
+		return toolError("A body explaining the requested changes is required"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	key := reviewBatchKey(owner, repo, prNumber)
+	batch, ok := s.reviewBatches.snapshot(key)
+	if !ok {
+		return toolError(fmt.Sprintf("No draft review is in progress on PR #%d. Call start_review first.", prNumber)), nil
+	}
+	if len(batch.comments) == 0 && body == "" && event == "COMMENT" {
+		return toolError("The draft review has no comments. Add some with add_review_comment_to_batch, or give the review a body."), nil
+	}
+
+	review := &github.PullRequestReviewRequest{
+		CommitID: github.String(batch.commitID),
+		Event:    github.String(event),
+		Comments: batch.comments,
+	}
+	if body != "" {
+		review.Body = github.String(body)
+	}
+
+	// The batch is kept on failure so the call can be retried.
+	result, resp, err := s.restClient.PullRequests.CreateReview(ctx, owner, repo, prNumber, review)
+	if err != nil {
+		message := githubErrorMessage(err)
+		if resp != nil && resp.StatusCode == http.StatusUnprocessableEntity && strings.Contains(strings.ToLower(message), "pending review") {
+			return toolError(fmt.Sprintf("GitHub already has a pending review from you on PR #%d; submit or discard it on GitHub, then call submit_review again (%s)", prNumber, message)), nil
+		}
+		if strings.Contains(strings.ToLower(message), "own pull request") {
+			return toolError(fmt.Sprintf("You cannot approve or request changes on your own pull request; submit with event COMMENT instead (%s)", message)), nil
+		}
+		return apiError("Failed to submit review", err)
+	}
+
+	s.reviewBatches.finish(key)
+	s.threadCache.invalidate(threadCacheKey(owner, repo, prNumber))
+	return mcp.NewToolResultText(fmt.Sprintf("Review submitted with %d comments (state: %s, review ID %d)\n%s", len(batch.comments), result.GetState(), result.GetID(), result.GetHTMLURL())), nil
+}
+
+// threadLine is the line used to order threads within a file, falling back
+// to the original line for outdated threads.
+func threadLine(thread reviewThread) int {
+	if len(thread.Comments.Nodes) == 0 {
+		return 0
+	}
+	firstComment := thread.Comments.Nodes[0]
+	if firstComment.Line > 0 {
+		return int(firstComment.Line)
+	}
+	return int(firstComment.OriginalLine)
+}
+
+func (s *githubService) getCommentsByFileHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	unresolvedOnly := req.GetBool("unresolved_only", false)
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	threads, stats, err := s.getReviewThreads(ctx, owner, repo, prNumber, req.GetBool("no_cache", false))
+	if err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	type fileThreads struct {
+		path       string
+		threads    []reviewThread
+		unresolved int
+	}
+
+	files := map[string]*fileThreads{}
+	threadCount := 0
+	for _, thread := range threads {
+		if len(thread.Comments.Nodes) == 0 || (unresolvedOnly && bool(thread.IsResolved)) {
+			continue
+		}
+
+		path := string(thread.Comments.Nodes[0].Path)
+		file, ok := files[path]
+		if !ok {
+			file = &fileThreads{path: path}
+			files[path] = file
+		}
+		file.threads = append(file.threads, thread)
+		if !thread.IsResolved {
+			file.unresolved++
+		}
+		threadCount++
+	}
+
+	if threadCount == 0 {
+		if unresolvedOnly {
+			return mcp.NewToolResultText("No unresolved comments found on that PR." + stats.truncationWarning()), nil
+		}
+		return mcp.NewToolResultText("No comments found on that PR." + stats.truncationWarning()), nil
+	}
+
+	sorted := make([]*fileThreads, 0, len(files))
+	for _, file := range files {
+		sort.SliceStable(file.threads, func(i, j int) bool {
+			return threadLine(file.threads[i]) < threadLine(file.threads[j])
+		})
+		sorted = append(sorted, file)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].unresolved != sorted[j].unresolved {
+			return sorted[i].unresolved > sorted[j].unresolved
+		}
+		return sorted[i].path < sorted[j].path
+	})
+
+	var responseBuilder strings.Builder
+	for _, file := range sorted {
+		responseBuilder.WriteString(fmt.Sprintf("=== %s (%d threads, %d unresolved) ===\n", file.path, len(file.threads), file.unresolved))
+
+		for _, thread := range file.threads {
+			status := "Resolved"
+			if !thread.IsResolved {
+				status = "Unresolved"
+			}
+			responseBuilder.WriteString(fmt.Sprintf("[%s] (%s)\n", status, threadLocation(thread)))
+
+			for _, comment := range thread.Comments.Nodes {
+				responseBuilder.WriteString(fmt.Sprintf("  - %s: %s\n", commentAuthorLine(comment, false), string(comment.Body)))
+			}
+			responseBuilder.WriteString(fmt.Sprintf("  (Thread Link: %s)\n\n", thread.Comments.Nodes[0].URL.String()))
+		}
+	}
+
+	filterText := ""
+	if unresolvedOnly {
+		filterText = " unresolved"
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Found %d%s comment threads across %d files:\n\n%s", threadCount, filterText, len(sorted), responseBuilder.String()) + stats.truncationWarning()), nil
+}
+
+// getThreadStatusHandler reports who resolved each review thread. GitHub does
+// not expose when a thread was resolved, so the latest comment time is shown
+// as the closest available signal.
+func (s *githubService) getThreadStatusHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	relative := req.GetBool("relative_time", false)
+
+	// Resolution state changes independently of comments, so always fetch
+	// fresh data rather than consulting the thread cache.
+	threads, stats, err := s.fetchReviewThreads(ctx, owner, repo, prNumber)
+	if err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	var responseBuilder strings.Builder
+	shown, resolved := 0, 0
+	for _, thread := range threads {
+		if len(thread.Comments.Nodes) == 0 {
+			continue
+		}
+		shown++
+
+		status := "unresolved"
+		if thread.IsResolved {
+			resolved++
+			status = "resolved"
+			if login := string(thread.ResolvedBy.Login); login != "" {
+				status = fmt.Sprintf("resolved by @%s", login)
+			}
+		}
+
+		first := thread.Comments.Nodes[0]
+		last := thread.Comments.Nodes[len(thread.Comments.Nodes)-1]
+		responseBuilder.WriteString(fmt.Sprintf("- [%s] %s (%s)\n", status, string(first.Path), threadLocation(thread)))
+		responseBuilder.WriteString(fmt.Sprintf("  Started by @%s, last comment %s\n", string(first.Author.Login), formatTimestamp(last.CreatedAt.Time, relative)))
+		responseBuilder.WriteString(fmt.Sprintf("  Thread ID: %v\n", thread.ID))
+		responseBuilder.WriteString(fmt.Sprintf("  (Thread Link: %s)\n\n", first.URL.String()))
+	}
+
+	if shown == 0 {
+		return mcp.NewToolResultText("No review threads found on that PR." + stats.truncationWarning()), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Found %d review threads (%d resolved, %d unresolved):\n\n%s", shown, resolved, shown-resolved, responseBuilder.String()) + stats.truncationWarning()), nil
+}
+
+// getStaleThreadsHandler lists unresolved threads whose comments GitHub marks
+// as outdated, i.e. the lines they were left on have since changed. Such
+// feedback may already be addressed or no longer apply.
+func (s *githubService) getStaleThreadsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	prURL, err := req.RequireString("pull_request_url")
+	if err != nil {
+		return toolError("Missing required argument: pull_request_url"), nil
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid PR URL: %v", err)), nil
+	}
+
+	threads, stats, err := s.getReviewThreads(ctx, owner, repo, prNumber, req.GetBool("no_cache", false))
+	if err != nil {
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	var responseBuilder strings.Builder
+	staleCount := 0
+	for _, thread := range threads {
+		if thread.IsResolved || !thread.IsOutdated || len(thread.Comments.Nodes) == 0 {
+			continue
+		}
+		staleCount++
+
+		firstComment := thread.Comments.Nodes[0]
+		responseBuilder.WriteString(fmt.Sprintf("Stale Thread on: %s (%s)\n", string(firstComment.Path), threadLocation(thread)))
+
+		preview, hiddenLines := commentPreview(string(firstComment.Body), 200, 3)
+		responseBuilder.WriteString(fmt.Sprintf("  - %s: %s\n", commentAuthorLine(firstComment, false), preview))
+		if hiddenLines > 0 {
+			responseBuilder.WriteString(fmt.Sprintf("… +%d lines\n", hiddenLines))
+		}
+		if replies := len(thread.Comments.Nodes) - 1; replies > 0 {
+			responseBuilder.WriteString(fmt.Sprintf("  (%d replies)\n", replies))
+		}
+		responseBuilder.WriteString(fmt.Sprintf("  (Thread Link: %s)\n  (Thread ID: %v)\n\n", firstComment.URL.String(), thread.ID))
+	}
+
+	if staleCount == 0 {
+		return mcp.NewToolResultText("No stale threads found on that PR: every unresolved thread is still on current code." + stats.truncationWarning()), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Found %d unresolved threads on outdated code:\n\n%s", staleCount, responseBuilder.String()) + stats.truncationWarning()), nil
+}
+
+func (s *githubService) serverInfoHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("GitHub MCP server version %s\n", serverVersion))
+	responseBuilder.WriteString(fmt.Sprintf("API base URL: %s\n", s.restClient.BaseURL.String()))
+
+	login, err := validateCredentials(ctx, s.restClient, s.isApp)
+	switch {
+	case err != nil:
+		responseBuilder.WriteString(fmt.Sprintf("REST authentication: failed (%s)\n", redactSecrets(err.Error())))
+	case s.isApp:
+		responseBuilder.WriteString("REST authentication: ok (GitHub App installation)\n")
+	default:
+		responseBuilder.WriteString(fmt.Sprintf("REST authentication: ok (@%s)\n", login))
+	}
+
+	var query viewerQuery
+	if err := s.graphqlClient.Query(ctx, &query, nil); err != nil {
+		responseBuilder.WriteString(fmt.Sprintf("GraphQL connectivity: failed (%s)\n", redactSecrets(err.Error())))
+	} else {
+		responseBuilder.WriteString(fmt.Sprintf("GraphQL connectivity: ok (viewer @%s)\n", string(query.Viewer.Login)))
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
 }
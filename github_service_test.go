@@ -0,0 +1,974 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/shurcooL/githubv4"
+)
+
+// fakeGraphQL is a graphqlQuerier that answers queries from a callback and
+// records the variables of every call.
+type fakeGraphQL struct {
+	mu     sync.Mutex
+	query  func(q interface{}, variables map[string]interface{}) error
+	mutate func(m interface{}, input githubv4.Input, variables map[string]interface{}) error
+	calls  []map[string]interface{}
+}
+
+func (f *fakeGraphQL) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	f.record(variables)
+	return f.query(q, variables)
+}
+
+func (f *fakeGraphQL) Mutate(ctx context.Context, m interface{}, input githubv4.Input, variables map[string]interface{}) error {
+	f.record(variables)
+	if f.mutate == nil {
+		return fmt.Errorf("unexpected mutation %T", m)
+	}
+	return f.mutate(m, input, variables)
+}
+
+// record copies variables, since callers reuse the map between pages.
+func (f *fakeGraphQL) record(variables map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	copied := make(map[string]interface{}, len(variables))
+	for k, v := range variables {
+		copied[k] = v
+	}
+	f.calls = append(f.calls, copied)
+}
+
+func (f *fakeGraphQL) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+func newTestService(gql graphqlQuerier) *githubService {
+	return &githubService{
+		graphqlClient:   gql,
+		threadCache:     newThreadCache(defaultThreadCacheTTL),
+		reviewBatches:   newReviewBatches(),
+		maxGraphQLPages: defaultMaxGraphQLPages,
+		httpTimeout:     defaultHTTPTimeout,
+	}
+}
+
+// withREST points the service's REST client at an httptest server running
+// handler, the way a GitHub Enterprise Server base URL is configured. handler
+// sees paths without the /api/v3 prefix. The server is closed when the test
+// ends.
+func withREST(t *testing.T, s *githubService, handler http.Handler) *githubService {
+	t.Helper()
+	server := httptest.NewServer(http.StripPrefix("/api/v3", handler))
+	t.Cleanup(server.Close)
+
+	restClient, _, err := newClients(server.Client(), server.URL, "")
+	if err != nil {
+		t.Fatalf("newClients: %v", err)
+	}
+	s.restClient = restClient
+	return s
+}
+
+// afterCursor returns the $after variable of a call, or "" when it is null.
+func afterCursor(t *testing.T, variables map[string]interface{}) string {
+	t.Helper()
+	after, ok := variables["after"].(*githubv4.String)
+	if !ok {
+		t.Fatalf("after variable has type %T, want *githubv4.String", variables["after"])
+	}
+	if after == nil {
+		return ""
+	}
+	return string(*after)
+}
+
+// threadPages serves total review threads in pages of pageSize, with cursors
+// "cursor-1", "cursor-2", ... between them.
+func threadPages(total, pageSize int) func(q interface{}, variables map[string]interface{}) error {
+	return func(q interface{}, variables map[string]interface{}) error {
+		query, ok := q.(*prCommentsQuery)
+		if !ok {
+			return fmt.Errorf("unexpected query %T", q)
+		}
+
+		page := 0
+		if after, _ := variables["after"].(*githubv4.String); after != nil {
+			if _, err := fmt.Sscanf(string(*after), "cursor-%d", &page); err != nil {
+				return err
+			}
+		}
+
+		threads := &query.Repository.PullRequest.ReviewThreads
+		for i := page * pageSize; i < total && i < (page+1)*pageSize; i++ {
+			threads.Nodes = append(threads.Nodes, reviewThread{ID: githubv4.ID(fmt.Sprintf("thread-%d", i))})
+		}
+		if (page+1)*pageSize < total {
+			threads.PageInfo = pageInfo{HasNextPage: true, EndCursor: githubv4.String(fmt.Sprintf("cursor-%d", page+1))}
+		}
+		return nil
+	}
+}
+
+func TestFetchReviewThreadsFollowsCursor(t *testing.T) {
+	gql := &fakeGraphQL{query: threadPages(250, 100)}
+	s := newTestService(gql)
+
+	threads, _, err := s.fetchReviewThreads(context.Background(), "owner", "repo", 7)
+	if err != nil {
+		t.Fatalf("fetchReviewThreads: %v", err)
+	}
+
+	if len(threads) != 250 {
+		t.Fatalf("got %d threads, want 250", len(threads))
+	}
+	for i, thread := range threads {
+		if want := fmt.Sprintf("thread-%d", i); thread.ID != githubv4.ID(want) {
+			t.Fatalf("thread %d has ID %v, want %s", i, thread.ID, want)
+		}
+	}
+
+	wantCursors := []string{"", "cursor-1", "cursor-2"}
+	if len(gql.calls) != len(wantCursors) {
+		t.Fatalf("made %d queries, want %d", len(gql.calls), len(wantCursors))
+	}
+	for i, want := range wantCursors {
+		if got := afterCursor(t, gql.calls[i]); got != want {
+			t.Errorf("query %d: after = %q, want %q", i, got, want)
+		}
+		if got := gql.calls[i]["prNumber"]; got != githubv4.Int(7) {
+			t.Errorf("query %d: prNumber = %v, want 7", i, got)
+		}
+	}
+}
+
+func TestFetchReviewThreadsLoadsRemainingComments(t *testing.T) {
+	const totalComments = 45
+	comment := func(i int) reviewComment {
+		return reviewComment{DatabaseID: githubv4.Int(i), Body: githubv4.String(fmt.Sprintf("comment %d", i))}
+	}
+
+	gql := &fakeGraphQL{}
+	gql.query = func(q interface{}, variables map[string]interface{}) error {
+		switch query := q.(type) {
+		case *prCommentsQuery:
+			thread := reviewThread{ID: githubv4.ID("thread-1")}
+			for i := 0; i < 20; i++ {
+				thread.Comments.Nodes = append(thread.Comments.Nodes, comment(i))
+			}
+			thread.Comments.PageInfo = pageInfo{HasNextPage: true, EndCursor: "c20"}
+			query.Repository.PullRequest.ReviewThreads.Nodes = []reviewThread{thread}
+			query.RateLimit = queryRateLimit{Cost: 1, Remaining: 4999}
+			return nil
+
+		case *threadCommentsQuery:
+			if variables["threadId"] != githubv4.ID("thread-1") {
+				return fmt.Errorf("threadId = %v, want thread-1", variables["threadId"])
+			}
+
+			// Serve the rest in pages of 15 to exercise the follow-up loop.
+			var start int
+			if _, err := fmt.Sscanf(afterCursor(t, variables), "c%d", &start); err != nil {
+				return err
+			}
+			comments := &query.Node.PullRequestReviewThread.Comments
+			for i := start; i < totalComments && i < start+15; i++ {
+				comments.Nodes = append(comments.Nodes, comment(i))
+			}
+			if start+15 < totalComments {
+				comments.PageInfo = pageInfo{HasNextPage: true, EndCursor: githubv4.String(fmt.Sprintf("c%d", start+15))}
+			}
+			query.RateLimit = queryRateLimit{Cost: 1, Remaining: githubv4.Int(4999 - start)}
+			return nil
+		}
+		return fmt.Errorf("unexpected query %T", q)
+	}
+
+	threads, stats, err := newTestService(gql).fetchReviewThreads(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("fetchReviewThreads: %v", err)
+	}
+	if len(threads) != 1 {
+		t.Fatalf("got %d threads, want 1", len(threads))
+	}
+
+	comments := threads[0].Comments.Nodes
+	if len(comments) != totalComments {
+		t.Fatalf("got %d comments, want %d", len(comments), totalComments)
+	}
+	for i, c := range comments {
+		if int(c.DatabaseID) != i {
+			t.Fatalf("comment %d has ID %d; comments are out of order", i, c.DatabaseID)
+		}
+	}
+	if threads[0].Comments.PageInfo.HasNextPage {
+		t.Error("thread still reports more comments after loading them all")
+	}
+
+	// One thread query and two follow-up pages (20-34, 35-44).
+	if got := gql.callCount(); got != 3 {
+		t.Errorf("made %d queries, want 3", got)
+	}
+	// The follow-up pages are part of the cost, and the last query reports
+	// the remaining points.
+	if stats.Cost != 3 || stats.Remaining != 4999-35 {
+		t.Errorf("stats = %+v, want Cost 3 and Remaining %d", stats, 4999-35)
+	}
+}
+
+func TestFetchReviewThreadsStopsAtPageCap(t *testing.T) {
+	gql := &fakeGraphQL{query: threadPages(250, 100)}
+	s := newTestService(gql)
+	s.maxGraphQLPages = 2
+
+	threads, stats, err := s.getReviewThreads(context.Background(), "owner", "repo", 7, false)
+	if err != nil {
+		t.Fatalf("getReviewThreads: %v", err)
+	}
+
+	if got := gql.callCount(); got != 2 {
+		t.Errorf("made %d queries, want the cap of 2", got)
+	}
+	if len(threads) != 200 {
+		t.Errorf("got %d threads, want the 200 from the first two pages", len(threads))
+	}
+	if !stats.Truncated || stats.PageCap != 2 {
+		t.Errorf("stats = %+v, want Truncated with PageCap 2", stats)
+	}
+	if !strings.Contains(stats.truncationWarning(), "stopped after 2 GraphQL pages") {
+		t.Errorf("truncationWarning() = %q", stats.truncationWarning())
+	}
+	if _, ok := s.threadCache.get(threadCacheKey("owner", "repo", 7), false); ok {
+		t.Error("truncated threads were cached")
+	}
+}
+
+func TestTruncationIsSurfacedByHandlers(t *testing.T) {
+	req := toolRequest(map[string]interface{}{
+		"pull_request_url": "https://github.com/owner/repo/pull/7",
+		"format":           "json",
+	})
+
+	// One page of threads is allowed out of two, so every handler below sees
+	// a truncated fetch.
+	newService := func() *githubService {
+		s := newTestService(&fakeGraphQL{query: threadPages(150, 100)})
+		s.maxGraphQLPages = 1
+		return s
+	}
+
+	jsonHandlers := map[string]profileHandler{
+		"get_threads_structured":  (*githubService).getThreadsStructuredHandler,
+		"get_unresolved_comments": (*githubService).getUnresolvedCommentsHandler,
+		"get_full_comments":       (*githubService).getFullCommentsHandler,
+	}
+	for name, handler := range jsonHandlers {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler(newService(), context.Background(), req)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+
+			var output threadListOutput
+			if err := json.Unmarshal([]byte(resultText(t, result)), &output); err != nil {
+				t.Fatalf("decoding JSON output: %v", err)
+			}
+			if !output.Truncated {
+				t.Error("JSON output does not report truncated: true")
+			}
+		})
+	}
+
+	textHandlers := map[string]profileHandler{
+		"get_unresolved_comments":      (*githubService).getUnresolvedCommentsHandler,
+		"get_comments_by_file":         (*githubService).getCommentsByFileHandler,
+		"get_thread_status":            (*githubService).getThreadStatusHandler,
+		"get_stale_threads":            (*githubService).getStaleThreadsHandler,
+		"get_pr_review_comments_since": (*githubService).getReviewCommentsSinceHandler,
+		"get_comments_for_prs":         (*githubService).getCommentsForPRsHandler,
+	}
+	textReq := toolRequest(map[string]interface{}{
+		"pull_request_url":  "https://github.com/owner/repo/pull/7",
+		"pull_request_urls": "https://github.com/owner/repo/pull/7",
+		"since":             "2024-01-01",
+	})
+	for name, handler := range textHandlers {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler(newService(), context.Background(), textReq)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if text := resultText(t, result); !strings.Contains(text, "stopped after 1 GraphQL pages") {
+				t.Errorf("output has no truncation warning:\n%s", text)
+			}
+		})
+	}
+}
+
+// testComment builds a review comment on path with a discussion URL.
+func testComment(id int, author, path, body string, created time.Time) reviewComment {
+	var c reviewComment
+	c.DatabaseID = githubv4.Int(id)
+	c.Author.Login = githubv4.String(author)
+	c.Path = githubv4.String(path)
+	c.Line = 10
+	c.Body = githubv4.String(body)
+	c.CreatedAt = githubv4.DateTime{Time: created}
+	c.URL = githubv4.URI{URL: &url.URL{Scheme: "https", Host: "github.com", Path: "/owner/repo/pull/7", Fragment: fmt.Sprintf("discussion_r%d", id)}}
+	return c
+}
+
+// servedThreads answers the review threads query with threads, in one page.
+func servedThreads(threads ...reviewThread) *fakeGraphQL {
+	return &fakeGraphQL{query: func(q interface{}, variables map[string]interface{}) error {
+		query, ok := q.(*prCommentsQuery)
+		if !ok {
+			return fmt.Errorf("unexpected query %T", q)
+		}
+		query.Repository.PullRequest.ReviewThreads.Nodes = threads
+		return nil
+	}}
+}
+
+// sampleThreads is one unresolved thread with a reply and one resolved
+// thread.
+func sampleThreads() []reviewThread {
+	created := time.Date(2024, 5, 1, 14, 3, 0, 0, time.UTC)
+
+	open := reviewThread{ID: "PRRT_open"}
+	open.Comments.Nodes = []reviewComment{
+		testComment(1, "alice", "main.go", "Please handle this error.", created),
+		testComment(2, "bob", "main.go", "Done.", created.Add(time.Hour)),
+	}
+
+	resolved := reviewThread{ID: "PRRT_resolved", IsResolved: true}
+	resolved.Comments.Nodes = []reviewComment{
+		testComment(3, "alice", "util.go", "Typo here.", created),
+	}
+
+	return []reviewThread{open, resolved}
+}
+
+func TestGetUnresolvedCommentsHandler(t *testing.T) {
+	s := newTestService(servedThreads(sampleThreads()...))
+
+	result, err := s.getUnresolvedCommentsHandler(context.Background(), toolRequest(map[string]interface{}{
+		"pull_request_url": "https://github.com/owner/repo/pull/7",
+	}))
+	if err != nil {
+		t.Fatalf("getUnresolvedCommentsHandler: %v", err)
+	}
+
+	text := resultText(t, result)
+	for _, want := range []string{
+		"Found 1 unresolved of 2 comment threads (1 resolved)",
+		"Unresolved Thread on: main.go (Line 10)",
+		"@alice (2024-05-01 14:03 UTC): Please handle this error.",
+		"@bob (2024-05-01 15:03 UTC): Done.",
+		"https://github.com/owner/repo/pull/7#discussion_r1",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output is missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "util.go") {
+		t.Errorf("output includes the resolved thread:\n%s", text)
+	}
+}
+
+func TestGetFullCommentsHandlerFiltersByAuthor(t *testing.T) {
+	s := newTestService(servedThreads(sampleThreads()...))
+
+	result, err := s.getFullCommentsHandler(context.Background(), toolRequest(map[string]interface{}{
+		"pull_request_url": "https://github.com/owner/repo/pull/7",
+		"author":           "@Bob",
+	}))
+	if err != nil {
+		t.Fatalf("getFullCommentsHandler: %v", err)
+	}
+
+	text := resultText(t, result)
+	for _, want := range []string{
+		"Found 1 comment threads with comments by @Bob",
+		"=== Unresolved Thread on: main.go (Line 10) ===",
+		"» @bob (2024-05-01 15:03 UTC):\nDone.",
+		"(Thread ID: PRRT_open)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output is missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "util.go") {
+		t.Errorf("output includes a thread without comments by @bob:\n%s", text)
+	}
+}
+
+func TestParsePRURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://github.com/owner/repo/pull/123"},
+		{url: "https://github.com/owner/repo/pull/123/"},
+		{url: "https://github.com/owner/repo/pull/123/files"},
+		{url: "https://github.com/owner/repo/pull/123/commits"},
+		{url: "https://github.com/owner/repo/pull/123/commits/abc123"},
+		{url: "https://github.com/owner/repo/pull/123#discussion_r456"},
+		{url: "https://github.com/owner/repo/pull/123/files?w=1"},
+		{url: "https://github.com/owner/repo/pull/123?w=1#discussion_r456"},
+		{url: "  https://github.com/owner/repo/pull/123  "},
+		{url: "https://ghe.example.com/owner/repo/pull/123"},
+		{url: "https://github.com/owner/repo/pull/123abc", wantErr: true},
+		{url: "https://github.com/owner/repo/issues/123", wantErr: true},
+		{url: "https://github.com/owner/pull/123", wantErr: true},
+		{url: "github.com/owner/repo/pull/123", wantErr: true},
+		{url: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			owner, repo, number, err := parsePRURL(tt.url)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parsePRURL(%q) = %s/%s#%d, want an error", tt.url, owner, repo, number)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePRURL(%q): %v", tt.url, err)
+			}
+			if owner != "owner" || repo != "repo" || number != 123 {
+				t.Errorf("parsePRURL(%q) = %s/%s#%d, want owner/repo#123", tt.url, owner, repo, number)
+			}
+		})
+	}
+}
+
+func TestCommentPreviewThresholds(t *testing.T) {
+	lines := func(n, width int) string {
+		var parts []string
+		for i := 0; i < n; i++ {
+			parts = append(parts, strings.Repeat("x", width))
+		}
+		return strings.Join(parts, "\n")
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantBody   string
+		wantHidden int
+	}{
+		{
+			// 4 lines of 49 chars plus 3 newlines = 199 chars.
+			name:     "just under max chars",
+			body:     lines(4, 49),
+			wantBody: lines(4, 49),
+		},
+		{
+			// 4 lines of 50 chars plus 3 newlines = 203 chars.
+			name:       "just over max chars",
+			body:       lines(4, 50),
+			wantBody:   lines(3, 50),
+			wantHidden: 1,
+		},
+		{
+			name:     "exactly max chars",
+			body:     strings.Repeat("x", 200),
+			wantBody: strings.Repeat("x", 200),
+		},
+		{
+			name:     "over max chars but exactly max lines",
+			body:     lines(3, 100),
+			wantBody: lines(3, 100),
+		},
+		{
+			name:       "over max chars and many lines",
+			body:       lines(10, 50),
+			wantBody:   lines(3, 50),
+			wantHidden: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hidden := commentPreview(tt.body, 200, 3)
+			if got != tt.wantBody || hidden != tt.wantHidden {
+				t.Errorf("commentPreview(%d chars) = (%d chars, %d hidden), want (%d chars, %d hidden)",
+					len(tt.body), len(got), hidden, len(tt.wantBody), tt.wantHidden)
+			}
+		})
+	}
+}
+
+// resultText returns the text of a single-text tool result.
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	if result == nil || len(result.Content) != 1 {
+		t.Fatalf("result = %+v, want one content item", result)
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("content has type %T, want mcp.TextContent", result.Content[0])
+	}
+	return text.Text
+}
+
+// toolRequest builds a tool call with the given arguments.
+func toolRequest(args map[string]interface{}) mcp.CallToolRequest {
+	var req mcp.CallToolRequest
+	req.Params.Arguments = args
+	return req
+}
+
+func TestPullRequestSearchQueryRepoFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  map[string]interface{}
+		state string
+		want  string
+	}{
+		{
+			name:  "no repo",
+			state: "open",
+			want:  "is:pr author:octocat is:open",
+		},
+		{
+			name:  "repo and state",
+			args:  map[string]interface{}{"repo": "owner/repo"},
+			state: "closed",
+			want:  "is:pr author:octocat is:closed repo:owner/repo",
+		},
+		{
+			name:  "repo with all states",
+			args:  map[string]interface{}{"repo": " owner/repo "},
+			state: "all",
+			want:  "is:pr author:octocat repo:owner/repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pullRequestSearchQuery(toolRequest(tt.args), tt.state, "octocat")
+			if err != nil {
+				t.Fatalf("pullRequestSearchQuery: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, repo := range []string{"owner", "owner/repo/extra", "/repo", "owner/"} {
+		if _, err := pullRequestSearchQuery(toolRequest(map[string]interface{}{"repo": repo}), "open", "octocat"); err == nil {
+			t.Errorf("repo %q was accepted, want an error", repo)
+		}
+	}
+}
+
+func TestPullRequestSearchQueryDateRange(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{
+			name: "created after",
+			args: map[string]interface{}{"created_after": "2024-01-15"},
+			want: "is:pr author:octocat is:open created:>=2024-01-15",
+		},
+		{
+			name: "updated after",
+			args: map[string]interface{}{"updated_after": " 2024-03-01 "},
+			want: "is:pr author:octocat is:open updated:>=2024-03-01",
+		},
+		{
+			name: "both with repo",
+			args: map[string]interface{}{"repo": "owner/repo", "created_after": "2023-12-31", "updated_after": "2024-02-29"},
+			want: "is:pr author:octocat is:open repo:owner/repo created:>=2023-12-31 updated:>=2024-02-29",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pullRequestSearchQuery(toolRequest(tt.args), "open", "octocat")
+			if err != nil {
+				t.Fatalf("pullRequestSearchQuery: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"created_after": "2024-1-15"},
+		{"created_after": "2024-02-30"},
+		{"updated_after": "15/01/2024"},
+		{"updated_after": "yesterday"},
+	} {
+		_, err := pullRequestSearchQuery(toolRequest(args), "open", "octocat")
+		if err == nil || !strings.Contains(err.Error(), "YYYY-MM-DD") {
+			t.Errorf("%v: err = %v, want a YYYY-MM-DD format error", args, err)
+		}
+	}
+}
+
+func TestPullRequestSearchOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantSort  string
+		wantOrder string
+	}{
+		{name: "defaults", wantSort: "updated", wantOrder: "desc"},
+		{name: "oldest created first", args: map[string]interface{}{"sort": "created", "order": "asc"}, wantSort: "created", wantOrder: "asc"},
+		{name: "most commented", args: map[string]interface{}{"sort": "comments"}, wantSort: "comments", wantOrder: "desc"},
+		{name: "order only", args: map[string]interface{}{"order": "asc"}, wantSort: "updated", wantOrder: "asc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := pullRequestSearchOptions(toolRequest(tt.args), 25)
+			if err != nil {
+				t.Fatalf("pullRequestSearchOptions: %v", err)
+			}
+			if opts.Sort != tt.wantSort || opts.Order != tt.wantOrder || opts.PerPage != 25 {
+				t.Errorf("options = {Sort: %q, Order: %q, PerPage: %d}, want {%q, %q, 25}", opts.Sort, opts.Order, opts.PerPage, tt.wantSort, tt.wantOrder)
+			}
+		})
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"sort": "popularity"},
+		{"order": "descending"},
+	} {
+		if _, err := pullRequestSearchOptions(toolRequest(args), 25); err == nil {
+			t.Errorf("%v was accepted, want an error", args)
+		}
+	}
+}
+
+// searchIssuesResponse is a canned /search/issues page: two of three
+// matching pull requests.
+const searchIssuesResponse = `{
+  "total_count": 3,
+  "incomplete_results": false,
+  "items": [
+    {"number": 12, "title": "Add retries", "state": "open", "html_url": "https://github.com/owner/repo/pull/12"},
+    {"number": 9, "title": "Fix cache key", "state": "open", "html_url": "https://github.com/owner/repo/pull/9"}
+  ]
+}`
+
+// searchServer serves searchIssuesResponse and records the query string of
+// every search request.
+func searchServer(queries *[]url.Values) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, searchIssuesResponse)
+	})
+	return mux
+}
+
+func TestListPullRequestsHandler(t *testing.T) {
+	var queries []url.Values
+	s := withREST(t, newTestService(nil), searchServer(&queries))
+	s.login = "octocat"
+
+	result, err := s.listPullRequestsHandler(context.Background(), toolRequest(map[string]interface{}{
+		"repo":  "owner/repo",
+		"limit": float64(2),
+	}))
+	if err != nil {
+		t.Fatalf("listPullRequestsHandler: %v", err)
+	}
+
+	if len(queries) != 1 {
+		t.Fatalf("made %d search requests, want 1", len(queries))
+	}
+	query := queries[0]
+	if got, want := query.Get("q"), "is:pr author:octocat is:open repo:owner/repo"; got != want {
+		t.Errorf("q = %q, want %q", got, want)
+	}
+	if query.Get("sort") != "updated" || query.Get("order") != "desc" || query.Get("per_page") != "2" {
+		t.Errorf("sort=%q order=%q per_page=%q, want updated, desc, 2", query.Get("sort"), query.Get("order"), query.Get("per_page"))
+	}
+
+	text := resultText(t, result)
+	for _, want := range []string{
+		"Found 3 pull requests authored by octocat (state: open), showing 2:",
+		"- [State: open] Add retries\n  https://github.com/owner/repo/pull/12",
+		"- [State: open] Fix cache key\n  https://github.com/owner/repo/pull/9",
+		paginationFooter(2, 3),
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestListPullRequestsHandlerJSON(t *testing.T) {
+	var queries []url.Values
+	s := withREST(t, newTestService(nil), searchServer(&queries))
+
+	result, err := s.listPullRequestsHandler(context.Background(), toolRequest(map[string]interface{}{
+		"format": "json",
+		"state":  "all",
+	}))
+	if err != nil {
+		t.Fatalf("listPullRequestsHandler: %v", err)
+	}
+
+	// Without a captured login the search falls back to @me.
+	if got, want := queries[0].Get("q"), "is:pr author:@me"; got != want {
+		t.Errorf("q = %q, want %q", got, want)
+	}
+
+	var output pullRequestListOutput
+	if err := json.Unmarshal([]byte(resultText(t, result)), &output); err != nil {
+		t.Fatalf("decoding JSON output: %v", err)
+	}
+	want := pullRequestListOutput{
+		Total:    3,
+		Returned: 2,
+		State:    "all",
+		PullRequests: []pullRequestSummary{
+			{Number: 12, Title: "Add retries", State: "open", URL: "https://github.com/owner/repo/pull/12"},
+			{Number: 9, Title: "Fix cache key", State: "open", URL: "https://github.com/owner/repo/pull/9"},
+		},
+	}
+	if !reflect.DeepEqual(output, want) {
+		t.Errorf("output = %+v, want %+v", output, want)
+	}
+}
+
+func TestNewGithubServiceCapturesLogin(t *testing.T) {
+	var userCalls int
+	var queries []url.Values
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/user", func(w http.ResponseWriter, r *http.Request) {
+		userCalls++
+		io.WriteString(w, `{"login":"octocat"}`)
+	})
+	mux.Handle("/api/v3/search/issues", http.StripPrefix("/api/v3", searchServer(&queries)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Setenv("GITHUB_BASE_URL", server.URL)
+	t.Setenv("GITHUB_SKIP_VALIDATION", "")
+	t.Setenv("GITHUB_HTTP_TIMEOUT", "45s")
+
+	s, err := newGithubService(context.Background(), server.Client(), false)
+	if err != nil {
+		t.Fatalf("newGithubService: %v", err)
+	}
+	if s.login != "octocat" {
+		t.Fatalf("login = %q, want octocat", s.login)
+	}
+	if s.httpTimeout != 45*time.Second {
+		t.Errorf("httpTimeout = %v, want GITHUB_HTTP_TIMEOUT", s.httpTimeout)
+	}
+
+	// Tools reuse the captured login instead of asking GitHub again.
+	for i := 0; i < 2; i++ {
+		if _, err := s.listPullRequestsHandler(context.Background(), toolRequest(nil)); err != nil {
+			t.Fatalf("listPullRequestsHandler: %v", err)
+		}
+	}
+	for i, query := range queries {
+		if got := query.Get("q"); !strings.Contains(got, "author:octocat") {
+			t.Errorf("search %d: q = %q, want author:octocat", i, got)
+		}
+	}
+	if userCalls != 1 {
+		t.Errorf("fetched the authenticated user %d times, want once at construction", userCalls)
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// checkGolden compares got with testdata/name, rewriting the file instead
+// when the test runs with -update.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("updating %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run go test -update to accept it):\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// TestGetThreadsStructuredGolden pins the JSON schema of
+// get_threads_structured, whose field names are part of the tool contract.
+func TestGetThreadsStructuredGolden(t *testing.T) {
+	// An outdated thread has no current line and reports its original one.
+	threads := sampleThreads()
+	threads[1].IsOutdated = true
+	threads[1].Comments.Nodes[0].Line = 0
+	threads[1].Comments.Nodes[0].OriginalLine = 4
+
+	s := newTestService(servedThreads(threads...))
+	result, err := s.getThreadsStructuredHandler(context.Background(), toolRequest(map[string]interface{}{
+		"pull_request_url": "https://github.com/owner/repo/pull/7",
+	}))
+	if err != nil {
+		t.Fatalf("getThreadsStructuredHandler: %v", err)
+	}
+
+	checkGolden(t, "threads_structured.golden", resultText(t, result)+"\n")
+}
+
+// graphqlRequest is the body the GraphQL client posts.
+type graphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// recordingGraphQLServer answers every GraphQL request with one review
+// thread and records the requests it received.
+func recordingGraphQLServer(t *testing.T, requests *[]graphqlRequest) graphqlQuerier {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body graphqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding GraphQL request: %v", err)
+		}
+		*requests = append(*requests, body)
+
+		hunk := ""
+		if body.Variables["withHunk"] == true {
+			hunk = `"diffHunk": "@@ -1 +1 @@",`
+		}
+		fmt.Fprintf(w, `{"data": {"rateLimit": {"cost": 1, "remaining": 4999}, "repository": {"pullRequest": {"reviewThreads": {
+			"nodes": [{"id": "PRRT_1", "comments": {"nodes": [{"databaseId": 1, %s "body": "Hi", "url": "https://github.com/owner/repo/pull/7#discussion_r1"}]}}]
+		}}}}}`, hunk)
+	}))
+	t.Cleanup(server.Close)
+	return githubv4.NewEnterpriseClient(server.URL, server.Client())
+}
+
+func TestDiffHunkIsOnlySelectedWhenRequested(t *testing.T) {
+	for _, withDiffHunk := range []bool{false, true} {
+		t.Run(fmt.Sprint("withDiffHunk=", withDiffHunk), func(t *testing.T) {
+			var requests []graphqlRequest
+			s := newTestService(recordingGraphQLServer(t, &requests))
+
+			getThreads := s.getReviewThreads
+			if withDiffHunk {
+				getThreads = s.getReviewThreadsWithDiffHunks
+			}
+			threads, _, err := getThreads(context.Background(), "owner", "repo", 7, false)
+			if err != nil {
+				t.Fatalf("getReviewThreads: %v", err)
+			}
+
+			if len(requests) != 1 {
+				t.Fatalf("made %d GraphQL requests, want 1", len(requests))
+			}
+			query := requests[0].Query
+			if !strings.Contains(query, "diffHunk @include(if: $withHunk)") || !strings.Contains(query, "$withHunk:Boolean!") {
+				t.Errorf("query does not select diffHunk conditionally:\n%s", query)
+			}
+			if got := requests[0].Variables["withHunk"]; got != withDiffHunk {
+				t.Errorf("withHunk = %v, want %v", got, withDiffHunk)
+			}
+			if got := string(threads[0].Comments.Nodes[0].DiffHunk) != ""; got != withDiffHunk {
+				t.Errorf("comment has a diff hunk: %v, want %v", got, withDiffHunk)
+			}
+		})
+	}
+}
+
+func TestThreadCacheServesHunklessCallsFromHunkFetch(t *testing.T) {
+	gql := &fakeGraphQL{query: threadPages(1, 100)}
+	s := newTestService(gql)
+	ctx := context.Background()
+
+	// Threads without hunks cannot serve a call that needs them.
+	if _, _, err := s.getReviewThreads(ctx, "owner", "repo", 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.getReviewThreadsWithDiffHunks(ctx, "owner", "repo", 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := gql.callCount(); got != 2 {
+		t.Fatalf("made %d queries, want a refetch for the diff hunks", got)
+	}
+
+	// Threads with hunks serve either kind of call.
+	if _, _, err := s.getReviewThreads(ctx, "owner", "repo", 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.getReviewThreadsWithDiffHunks(ctx, "owner", "repo", 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := gql.callCount(); got != 2 {
+		t.Errorf("made %d queries, want the cached threads with hunks reused", got)
+	}
+}
+
+func TestFetchChecksPaginatesCombinedStatus(t *testing.T) {
+	var statusPages []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/commits/abc123/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"total_count": 1, "check_runs": [{"name": "build", "status": "completed", "conclusion": "success"}]}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/commits/abc123/status", func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		statusPages = append(statusPages, page)
+		if page == "" || page == "1" {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v3/repos/owner/repo/commits/abc123/status?page=2>; rel="next"`, r.Host))
+			io.WriteString(w, `{"statuses": [{"context": "ci/lint", "state": "success"}]}`)
+			return
+		}
+		io.WriteString(w, `{"statuses": [{"context": "ci/deploy", "state": "error"}]}`)
+	})
+	s := withREST(t, newTestService(nil), mux)
+
+	checks, err := s.fetchChecks(context.Background(), "owner", "repo", "abc123")
+	if err != nil {
+		t.Fatalf("fetchChecks: %v", err)
+	}
+
+	want := []checkResult{
+		{Name: "build", Conclusion: "success"},
+		{Name: "ci/lint", Conclusion: "success"},
+		{Name: "ci/deploy", Conclusion: "failure"},
+	}
+	if !reflect.DeepEqual(checks, want) {
+		t.Errorf("checks = %+v, want %+v", checks, want)
+	}
+	if len(statusPages) != 2 {
+		t.Errorf("fetched %d pages of commit statuses, want 2", len(statusPages))
+	}
+}
@@ -0,0 +1,639 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// issueURLRegex matches an issue URL on any GitHub host, with the same
+// tolerance for suffixes as prURLRegex.
+var issueURLRegex = regexp.MustCompile(`^https?://[^/]+/([^/]+)/([^/]+)/issues/(\d+)(?:[/?#].*)?$`)
+
+func parseIssueURL(url string) (owner string, repo string, number int, err error) {
+	matches := issueURLRegex.FindStringSubmatch(strings.TrimSpace(url))
+	if len(matches) != 4 {
+		return "", "", 0, fmt.Errorf("invalid issue URL format. Expected: .../owner/repo/issues/123")
+	}
+
+	owner = matches[1]
+	repo = matches[2]
+	number, err = strconv.Atoi(matches[3])
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid issue number: %s", matches[3])
+	}
+
+	return owner, repo, number, nil
+}
+
+// issueFromRequest identifies the issue a tool operates on, either from an
+// "issue_url" argument or from a "repo" and "number" pair. Pull request URLs
+// are accepted too, since every pull request is also an issue.
+func issueFromRequest(req mcp.CallToolRequest) (owner string, repo string, number int, err error) {
+	if issueURL := req.GetString("issue_url", ""); issueURL != "" {
+		if owner, repo, number, err := parsePRURL(issueURL); err == nil {
+			return owner, repo, number, nil
+		}
+		return parseIssueURL(issueURL)
+	}
+
+	repoArg := req.GetString("repo", "")
+	number = req.GetInt("number", 0)
+	if repoArg == "" || number <= 0 {
+		return "", "", 0, fmt.Errorf("provide either issue_url or both repo and number")
+	}
+
+	owner, repo, err = parseRepo(repoArg)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	return owner, repo, number, nil
+}
+
+// splitList parses a comma-separated argument such as "bug, help wanted"
+// into its trimmed, non-empty elements.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (s *githubService) createIssueHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	title := strings.TrimSpace(req.GetString("title", ""))
+	if title == "" {
+		return toolError("Issue title must not be empty"), nil
+	}
+
+	issueRequest := &github.IssueRequest{
+		Title: github.String(title),
+	}
+	if body := req.GetString("body", ""); body != "" {
+		issueRequest.Body = github.String(body)
+	}
+	if labels := splitList(req.GetString("labels", "")); len(labels) > 0 {
+		issueRequest.Labels = &labels
+	}
+	if assignees := splitList(req.GetString("assignees", "")); len(assignees) > 0 {
+		issueRequest.Assignees = &assignees
+	}
+
+	issue, _, err := s.restClient.Issues.Create(ctx, owner, repo, issueRequest)
+	if err != nil {
+		return apiError("Failed to create issue", err)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Created issue #%d: %s\n%s", issue.GetNumber(), issue.GetTitle(), issue.GetHTMLURL())), nil
+}
+
+func (s *githubService) listIssuesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	state := req.GetString("state", "open")
+	limit := getLimit(req, 30)
+
+	opts := &github.IssueListByRepoOptions{
+		State:       state,
+		Labels:      splitList(req.GetString("labels", "")),
+		Assignee:    req.GetString("assignee", ""),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var issues []*github.Issue
+	morePages := false
+	for len(issues) < limit {
+		page, resp, err := s.restClient.Issues.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			return apiError("Failed to list issues", err)
+		}
+
+		// The issues API also returns pull requests, which have their own tools.
+		for _, issue := range page {
+			if !issue.IsPullRequest() {
+				issues = append(issues, issue)
+			}
+		}
+
+		morePages = resp.NextPage != 0
+		if !morePages {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	// The issues API has no total count, so it is only looked up through
+	// search when the limit cut the listing short.
+	total := len(issues)
+	if morePages || len(issues) > limit {
+		result, _, err := s.restClient.Search.Issues(ctx, issueCountQuery(owner, repo, opts), &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 1}})
+		if err != nil {
+			return apiError("Failed to count issues", err)
+		}
+		total = max(result.GetTotal(), limit)
+		issues = issues[:min(len(issues), limit)]
+	}
+
+	if len(issues) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No issues found in %s/%s with state: %s", owner, repo, state)), nil
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %d issues in %s/%s (state: %s), showing %d:\n\n", total, owner, repo, state, len(issues)))
+	for _, issue := range issues {
+		responseBuilder.WriteString(fmt.Sprintf("- #%d [State: %s] %s\n  %s\n",
+			issue.GetNumber(),
+			issue.GetState(),
+			issue.GetTitle(),
+			issue.GetHTMLURL(),
+		))
+	}
+
+	responseBuilder.WriteString(paginationFooter(len(issues), total))
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+// issueCountQuery builds the issue search query that matches the same issues
+// as listing opts on owner/repo.
+func issueCountQuery(owner, repo string, opts *github.IssueListByRepoOptions) string {
+	queryParts := []string{fmt.Sprintf("repo:%s/%s", owner, repo), "is:issue"}
+	if opts.State == "open" || opts.State == "closed" {
+		queryParts = append(queryParts, fmt.Sprintf("is:%s", opts.State))
+	}
+	for _, label := range opts.Labels {
+		queryParts = append(queryParts, fmt.Sprintf("label:%q", label))
+	}
+	switch opts.Assignee {
+	case "":
+	case "none":
+		queryParts = append(queryParts, "no:assignee")
+	case "*":
+		queryParts = append(queryParts, "-no:assignee")
+	default:
+		queryParts = append(queryParts, fmt.Sprintf("assignee:%s", opts.Assignee))
+	}
+	return strings.Join(queryParts, " ")
+}
+
+func (s *githubService) commentOnIssueHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	body := strings.TrimSpace(req.GetString("body", ""))
+	if body == "" {
+		return toolError("Comment body must not be empty"), nil
+	}
+
+	owner, repo, number, err := issueFromRequest(req)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid issue reference: %v", err)), nil
+	}
+
+	comment, resp, err := s.restClient.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{
+		Body: github.String(body),
+	})
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound) {
+			return toolError(fmt.Sprintf("Permission denied: the token cannot comment on %s/%s#%d (%s)", owner, repo, number, githubErrorMessage(err))), nil
+		}
+		return apiError("Failed to comment on issue", err)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Comment posted: %s", comment.GetHTMLURL())), nil
+}
+
+func (s *githubService) closeIssueHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	stateReason := req.GetString("state_reason", "completed")
+	if stateReason != "completed" && stateReason != "not_planned" {
+		return toolError(fmt.Sprintf("Invalid state_reason %q. Expected completed or not_planned", stateReason)), nil
+	}
+
+	return s.setIssueState(ctx, req, "closed", stateReason)
+}
+
+func (s *githubService) reopenIssueHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.setIssueState(ctx, req, "open", "reopened")
+}
+
+func (s *githubService) setIssueState(ctx context.Context, req mcp.CallToolRequest, state, stateReason string) (*mcp.CallToolResult, error) {
+	owner, repo, number, err := issueFromRequest(req)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid issue reference: %v", err)), nil
+	}
+
+	issue, _, err := s.restClient.Issues.Edit(ctx, owner, repo, number, &github.IssueRequest{
+		State:       github.String(state),
+		StateReason: github.String(stateReason),
+	})
+	if err != nil {
+		return apiError("Failed to update issue", err)
+	}
+
+	status := issue.GetState()
+	if issue.GetStateReason() != "" {
+		status = fmt.Sprintf("%s (%s)", status, issue.GetStateReason())
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Issue #%d is now %s\n%s", issue.GetNumber(), status, issue.GetHTMLURL())), nil
+}
+
+// lockReasons are the reasons GitHub accepts for locking a conversation.
+var lockReasons = []string{"off-topic", "too heated", "resolved", "spam"}
+
+func (s *githubService) lockConversationHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	reason := strings.TrimSpace(req.GetString("reason", ""))
+	if reason != "" && !slices.Contains(lockReasons, reason) {
+		return toolError(fmt.Sprintf("Invalid reason %q. Expected one of: %s", reason, strings.Join(lockReasons, ", "))), nil
+	}
+
+	return s.setConversationLock(ctx, req, true, reason)
+}
+
+func (s *githubService) unlockConversationHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.setConversationLock(ctx, req, false, "")
+}
+
+// setConversationLock locks or unlocks the conversation of an issue or pull
+// request. A conversation already in the requested state is left alone, so
+// repeating a call is harmless.
+func (s *githubService) setConversationLock(ctx context.Context, req mcp.CallToolRequest, locked bool, reason string) (*mcp.CallToolResult, error) {
+	owner, repo, number, err := issueFromRequest(req)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid issue reference: %v", err)), nil
+	}
+
+	issue, _, err := s.restClient.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return apiError("Failed to get issue", err)
+	}
+
+	alreadyDone := issue.GetLocked() == locked && (!locked || reason == "" || reason == issue.GetActiveLockReason())
+	if !alreadyDone {
+		if locked {
+			var opts *github.LockIssueOptions
+			if reason != "" {
+				opts = &github.LockIssueOptions{LockReason: reason}
+			}
+			_, err = s.restClient.Issues.Lock(ctx, owner, repo, number, opts)
+		} else {
+			_, err = s.restClient.Issues.Unlock(ctx, owner, repo, number)
+		}
+		if err != nil {
+			return apiError("Failed to update conversation lock", err)
+		}
+
+		issue, _, err = s.restClient.Issues.Get(ctx, owner, repo, number)
+		if err != nil {
+			return apiError("Failed to get issue", err)
+		}
+	}
+
+	status := "unlocked"
+	if issue.GetLocked() {
+		status = "locked"
+		if lockReason := issue.GetActiveLockReason(); lockReason != "" {
+			status = fmt.Sprintf("locked (%s)", lockReason)
+		}
+	}
+
+	prefix := ""
+	if alreadyDone {
+		prefix = "No change: "
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("%sThe conversation on %s/%s#%d is %s\n%s", prefix, owner, repo, number, status, issue.GetHTMLURL())), nil
+}
+
+func (s *githubService) getIssueHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	owner, repo, number, err := issueFromRequest(req)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid issue reference: %v", err)), nil
+	}
+
+	issue, _, err := s.restClient.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return apiError("Failed to get issue", err)
+	}
+
+	var comments []*github.IssueComment
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := s.restClient.Issues.ListComments(ctx, owner, repo, number, opts)
+		if err != nil {
+			return apiError("Failed to list issue comments", err)
+		}
+		comments = append(comments, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	var labels []string
+	for _, label := range issue.Labels {
+		labels = append(labels, label.GetName())
+	}
+
+	var assignees []string
+	for _, assignee := range issue.Assignees {
+		assignees = append(assignees, "@"+assignee.GetLogin())
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("=== #%d %s ===\n", issue.GetNumber(), issue.GetTitle()))
+	responseBuilder.WriteString(fmt.Sprintf("State: %s\n", issue.GetState()))
+	responseBuilder.WriteString(fmt.Sprintf("Author: @%s (%s)\n", issue.GetUser().GetLogin(), formatTimestamp(issue.GetCreatedAt().Time, false)))
+	if len(labels) > 0 {
+		responseBuilder.WriteString(fmt.Sprintf("Labels: %s\n", strings.Join(labels, ", ")))
+	}
+	if len(assignees) > 0 {
+		responseBuilder.WriteString(fmt.Sprintf("Assignees: %s\n", strings.Join(assignees, ", ")))
+	}
+	responseBuilder.WriteString(fmt.Sprintf("URL: %s\n\n", issue.GetHTMLURL()))
+
+	if body := issue.GetBody(); body != "" {
+		responseBuilder.WriteString(fmt.Sprintf("%s\n", body))
+	} else {
+		responseBuilder.WriteString("(no description)\n")
+	}
+
+	responseBuilder.WriteString(fmt.Sprintf("\n=== %d comments ===\n", len(comments)))
+	for _, comment := range comments {
+		responseBuilder.WriteString(fmt.Sprintf(
+			"\n--- @%s (%s) ---\n%s\n",
+			comment.GetUser().GetLogin(),
+			formatTimestamp(comment.GetCreatedAt().Time, false),
+			comment.GetBody(),
+		))
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+// listRepoLabels returns the names of every label defined in a repository.
+func (s *githubService) listRepoLabels(ctx context.Context, owner, repo string) ([]string, error) {
+	var names []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		labels, resp, err := s.restClient.Issues.ListLabels(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range labels {
+			names = append(names, label.GetName())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return names, nil
+}
+
+// resolveLabels maps the requested names onto the repository's labels,
+// matching case-insensitively as GitHub does. Names that do not exist are
+// reported together with the available labels.
+func (s *githubService) resolveLabels(ctx context.Context, owner, repo string, requested []string) ([]string, error) {
+	available, err := s.listRepoLabels(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	byName := make(map[string]string, len(available))
+	for _, name := range available {
+		byName[strings.ToLower(name)] = name
+	}
+
+	var resolved, unknown []string
+	for _, name := range requested {
+		if canonical, ok := byName[strings.ToLower(name)]; ok {
+			resolved = append(resolved, canonical)
+		} else {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		list := "(none)"
+		if len(available) > 0 {
+			list = strings.Join(available, ", ")
+		}
+		return nil, fmt.Errorf("unknown labels in %s/%s: %s. Available labels: %s", owner, repo, strings.Join(unknown, ", "), list)
+	}
+
+	return resolved, nil
+}
+
+func labelSetText(owner, repo string, number int, labels []*github.Label) string {
+	if len(labels) == 0 {
+		return fmt.Sprintf("%s/%s#%d now has no labels.", owner, repo, number)
+	}
+
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, label.GetName())
+	}
+	return fmt.Sprintf("Labels on %s/%s#%d: %s", owner, repo, number, strings.Join(names, ", "))
+}
+
+func (s *githubService) addLabelsToIssueHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	requested := splitList(req.GetString("labels", ""))
+	if len(requested) == 0 {
+		return toolError("Missing required argument: labels"), nil
+	}
+
+	owner, repo, number, err := issueFromRequest(req)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid issue reference: %v", err)), nil
+	}
+
+	labels, err := s.resolveLabels(ctx, owner, repo, requested)
+	if err != nil {
+		return apiError("Failed to resolve labels", err)
+	}
+
+	result, _, err := s.restClient.Issues.AddLabelsToIssue(ctx, owner, repo, number, labels)
+	if err != nil {
+		return apiError("Failed to add labels", err)
+	}
+
+	return mcp.NewToolResultText(labelSetText(owner, repo, number, result)), nil
+}
+
+func (s *githubService) removeLabelFromIssueHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := strings.TrimSpace(req.GetString("label", ""))
+	if name == "" {
+		return toolError("Missing required argument: label"), nil
+	}
+
+	owner, repo, number, err := issueFromRequest(req)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid issue reference: %v", err)), nil
+	}
+
+	labels, err := s.resolveLabels(ctx, owner, repo, []string{name})
+	if err != nil {
+		return apiError("Failed to resolve labels", err)
+	}
+
+	if _, err := s.restClient.Issues.RemoveLabelForIssue(ctx, owner, repo, number, labels[0]); err != nil {
+		return apiError("Failed to remove label", err)
+	}
+
+	issue, _, err := s.restClient.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return apiError(fmt.Sprintf("Removed label %q, but failed to fetch the remaining labels", labels[0]), err)
+	}
+
+	return mcp.NewToolResultText(labelSetText(owner, repo, number, issue.Labels)), nil
+}
+
+// listMilestones pages through the milestones of a repository in the given
+// state (open, closed, or all).
+func (s *githubService) listMilestones(ctx context.Context, owner, repo, state string) ([]*github.Milestone, error) {
+	var milestones []*github.Milestone
+	opts := &github.MilestoneListOptions{
+		State:       state,
+		Sort:        "due_on",
+		Direction:   "asc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		page, resp, err := s.restClient.Issues.ListMilestones(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return milestones, nil
+}
+
+func (s *githubService) listMilestonesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	state := req.GetString("state", "open")
+	milestones, err := s.listMilestones(ctx, owner, repo, state)
+	if err != nil {
+		return apiError("Failed to list milestones", err)
+	}
+
+	if len(milestones) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No %s milestones found in %s/%s.", state, owner, repo)), nil
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %d %s milestones in %s/%s:\n\n", len(milestones), state, owner, repo))
+	for _, milestone := range milestones {
+		due := "no due date"
+		if milestone.DueOn != nil {
+			due = "due " + milestone.GetDueOn().Format("2006-01-02")
+		}
+		responseBuilder.WriteString(fmt.Sprintf("- %s (#%d, %s, %s): %d open, %d closed issues\n  %s\n",
+			milestone.GetTitle(),
+			milestone.GetNumber(),
+			milestone.GetState(),
+			due,
+			milestone.GetOpenIssues(),
+			milestone.GetClosedIssues(),
+			milestone.GetHTMLURL(),
+		))
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+// resolveMilestone finds a milestone by number or case-insensitive title.
+// When none matches, the error lists the open milestones.
+func resolveMilestone(owner, repo, requested string, milestones []*github.Milestone) (*github.Milestone, error) {
+	number, _ := strconv.Atoi(strings.TrimPrefix(requested, "#"))
+	for _, milestone := range milestones {
+		if (number > 0 && milestone.GetNumber() == number) || strings.EqualFold(milestone.GetTitle(), requested) {
+			return milestone, nil
+		}
+	}
+
+	var open []string
+	for _, milestone := range milestones {
+		if milestone.GetState() == "open" {
+			open = append(open, milestone.GetTitle())
+		}
+	}
+	list := "(none)"
+	if len(open) > 0 {
+		list = strings.Join(open, ", ")
+	}
+	return nil, fmt.Errorf("unknown milestone %q in %s/%s. Open milestones: %s", requested, owner, repo, list)
+}
+
+func (s *githubService) setMilestoneHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	requested := strings.TrimSpace(req.GetString("milestone", ""))
+	if requested == "" {
+		return toolError("Missing required argument: milestone"), nil
+	}
+
+	owner, repo, number, err := issueFromRequest(req)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid issue reference: %v", err)), nil
+	}
+
+	milestones, err := s.listMilestones(ctx, owner, repo, "all")
+	if err != nil {
+		return apiError("Failed to list milestones", err)
+	}
+
+	milestone, err := resolveMilestone(owner, repo, requested, milestones)
+	if err != nil {
+		return toolError(err.Error()), nil
+	}
+
+	issue, _, err := s.restClient.Issues.Edit(ctx, owner, repo, number, &github.IssueRequest{
+		Milestone: github.Int(milestone.GetNumber()),
+	})
+	if err != nil {
+		return apiError("Failed to set milestone", err)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("%s/%s#%d is now in milestone %s.\n%s", owner, repo, number, milestone.GetTitle(), issue.GetHTMLURL())), nil
+}
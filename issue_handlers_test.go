@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// issueListServer serves two pages of two issues each, with a pull request
+// mixed into the first page, and records the issue search queries it gets.
+func issueListServer(queries *[]url.Values) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/issues", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			io.WriteString(w, `[{"number":3,"title":"Third"},{"number":4,"title":"Fourth"}]`)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v3/repos/owner/repo/issues?page=2>; rel="next"`, r.Host))
+		io.WriteString(w, `[{"number":1,"title":"First"},{"number":9,"title":"A PR","pull_request":{}},{"number":2,"title":"Second"}]`)
+	})
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"total_count":57,"items":[]}`)
+	})
+	return mux
+}
+
+func TestListIssuesHandlerReportsTotal(t *testing.T) {
+	var queries []url.Values
+	s := withREST(t, newTestService(nil), issueListServer(&queries))
+
+	result, err := s.listIssuesHandler(context.Background(), toolRequest(map[string]interface{}{
+		"repo":     "owner/repo",
+		"limit":    float64(2),
+		"labels":   "bug,good first issue",
+		"assignee": "none",
+	}))
+	if err != nil {
+		t.Fatalf("listIssuesHandler: %v", err)
+	}
+	text := resultText(t, result)
+
+	if len(queries) != 1 {
+		t.Fatalf("made %d search requests, want 1", len(queries))
+	}
+	wantQuery := `repo:owner/repo is:issue is:open label:"bug" label:"good first issue" no:assignee`
+	if got := queries[0].Get("q"); got != wantQuery {
+		t.Errorf("search query = %q, want %q", got, wantQuery)
+	}
+	for _, want := range []string{"Found 57 issues in owner/repo (state: open), showing 2:", "] First", "] Second", "Showing 2 of 57"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("result missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "A PR") {
+		t.Errorf("result lists a pull request:\n%s", text)
+	}
+}
+
+func TestListIssuesHandlerSkipsCountWhenComplete(t *testing.T) {
+	var queries []url.Values
+	s := withREST(t, newTestService(nil), issueListServer(&queries))
+
+	result, err := s.listIssuesHandler(context.Background(), toolRequest(map[string]interface{}{
+		"repo":  "owner/repo",
+		"limit": float64(10),
+	}))
+	if err != nil {
+		t.Fatalf("listIssuesHandler: %v", err)
+	}
+	text := resultText(t, result)
+
+	if len(queries) != 0 {
+		t.Errorf("made %d search requests, want 0", len(queries))
+	}
+	if !strings.Contains(text, "Found 4 issues in owner/repo (state: open), showing 4:") {
+		t.Errorf("unexpected header:\n%s", text)
+	}
+	if strings.Contains(text, "more available") {
+		t.Errorf("complete listing has a pagination footer:\n%s", text)
+	}
+}
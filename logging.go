@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// newLogger returns a structured logger writing to w at the level named by
+// LOG_LEVEL (debug, info, warn or error; info by default). The stdio
+// transport owns stdout, so w must never be os.Stdout.
+func newLogger(w io.Writer) (*slog.Logger, error) {
+	level := slog.LevelInfo
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL %q: must be debug, info, warn or error", value)
+		}
+	}
+
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})), nil
+}
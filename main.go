@@ -1,27 +1,43 @@
 package main
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// serverVersion is reported to MCP clients and by the server_info tool.
+const serverVersion = "1.0.0"
+
 func main() {
-	log.Println("Starting GitHub MCP Server...")
+	logger, err := newLogger(os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, redactSecrets(err.Error()))
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	slog.Info("Starting GitHub MCP Server", "version", serverVersion)
 
-	ghService, err := newGithubService()
+	profiles, err := newServiceProfiles()
 	if err != nil {
-		log.Fatalf("Failed to create GitHub service: %v", err)
+		slog.Error("Failed to create GitHub service", "error", redactSecrets(err.Error()))
+		os.Exit(1)
 	}
-	log.Println("GitHub service initialized successfully.")
+	slog.Info("GitHub service initialized", "profiles", profiles.names(), "primary", profiles.primary)
 
-	s := server.NewMCPServer("GitHub MCP", "1.0.0")
+	s := server.NewMCPServer("GitHub MCP", serverVersion)
 
 	// 3. Define the tool for listing PRs
 	listPRsTool := mcp.NewTool(
 		"list_pull_requests",
-		mcp.WithDescription("Lists pull requests authored by the authenticated user."),
+		mcp.WithDescription("Lists pull requests authored by the authenticated user, or by another user when author is set."),
 
 		// Add an optional string argument for "state"
 		mcp.WithString(
@@ -29,10 +45,49 @@ func main() {
 			mcp.Description("The state of the pull requests to list (open, closed, or all). Defaults to 'open'."),
 			mcp.Enum("open", "closed", "all"), // This helps Claude know the valid options
 		),
+		mcp.WithString(
+			"author",
+			mcp.Description("GitHub login of the pull request author. Defaults to '@me', the authenticated user."),
+		),
+		mcp.WithString(
+			"assignee",
+			mcp.Description("Optional GitHub login to filter pull requests by assignee."),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("Optional repository in the form owner/name to scope the listing to."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of pull requests to return (1-100). Defaults to 15."),
+		),
+		mcp.WithString(
+			"created_after",
+			mcp.Description("Optional date (YYYY-MM-DD); only include pull requests created on or after it."),
+		),
+		mcp.WithString(
+			"updated_after",
+			mcp.Description("Optional date (YYYY-MM-DD); only include pull requests updated on or after it."),
+		),
+		mcp.WithString(
+			"sort",
+			mcp.Description("Field to sort pull requests by. Defaults to 'updated'."),
+			mcp.Enum("created", "updated", "comments"),
+		),
+		mcp.WithString(
+			"order",
+			mcp.Description("Sort direction. Defaults to 'desc'; use 'asc' to list the oldest first."),
+			mcp.Enum("asc", "desc"),
+		),
+		mcp.WithString(
+			"format",
+			mcp.Description("Output format: 'text' for a readable summary or 'json' for structured data. Defaults to 'text'."),
+			mcp.Enum("text", "json"),
+		),
 	)
 
 	// 4. Add the tool to the server, passing our service's handler function.
-	s.AddTool(listPRsTool, ghService.listPullRequestsHandler)
+	profiles.addTool(s, listPRsTool, (*githubService).listPullRequestsHandler)
 
 	getUnresolvedCommentsTool := mcp.NewTool(
 		"get_unresolved_comments",
@@ -42,10 +97,35 @@ func main() {
 			mcp.Required(),
 			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
 		),
+		mcp.WithBoolean(
+			"relative_time",
+			mcp.Description("If true, show comment times relative to now (e.g., '3 days ago') instead of UTC timestamps. Defaults to false."),
+		),
+		mcp.WithNumber(
+			"max_preview_chars",
+			mcp.Description("Comments longer than this many characters are shortened to a preview. Defaults to 200."),
+		),
+		mcp.WithNumber(
+			"max_preview_lines",
+			mcp.Description("Number of lines shown in a shortened comment preview. Defaults to 3."),
+		),
+		mcp.WithBoolean(
+			"no_cache",
+			mcp.Description("If true, bypass the short-lived cache of review threads and fetch fresh data. Defaults to false."),
+		),
+		mcp.WithBoolean(
+			"show_cost",
+			mcp.Description("If true, start the text output with the GraphQL rate limit cost of fetching the threads. Defaults to false."),
+		),
+		mcp.WithString(
+			"format",
+			mcp.Description("Output format: 'text' for a readable summary or 'json' for structured data. Defaults to 'text'."),
+			mcp.Enum("text", "json"),
+		),
 	)
 
 	// 6. Add the new comments tool to the server
-	s.AddTool(getUnresolvedCommentsTool, ghService.getUnresolvedCommentsHandler)
+	profiles.addTool(s, getUnresolvedCommentsTool, (*githubService).getUnresolvedCommentsHandler)
 
 	// 7. Tool to get full comment details without truncation
 	getFullCommentsTool := mcp.NewTool(
@@ -60,13 +140,1580 @@ func main() {
 			"unresolved_only",
 			mcp.Description("If true, only show unresolved comments. If false, show all comments. Defaults to false."),
 		),
+		mcp.WithBoolean(
+			"include_diff_hunk",
+			mcp.Description("If true, show the diff hunk each thread was left on above its comments. Defaults to false."),
+		),
+		mcp.WithString(
+			"author",
+			mcp.Description("Optional GitHub login. Only threads with at least one comment by this user are shown, and their comments are marked with »."),
+		),
+		mcp.WithBoolean(
+			"no_cache",
+			mcp.Description("If true, bypass the short-lived cache of review threads and fetch fresh data. Defaults to false."),
+		),
+		mcp.WithBoolean(
+			"show_cost",
+			mcp.Description("If true, start the text output with the GraphQL rate limit cost of fetching the threads. Defaults to false."),
+		),
+		mcp.WithBoolean(
+			"relative_time",
+			mcp.Description("If true, show comment times relative to now (e.g., '3 days ago') instead of UTC timestamps. Defaults to false."),
+		),
+		mcp.WithString(
+			"format",
+			mcp.Description("Output format: 'text' for a readable summary or 'json' for structured data. Defaults to 'text'."),
+			mcp.Enum("text", "json"),
+		),
 	)
 
 	// 8. Add the full comments tool to the server
-	s.AddTool(getFullCommentsTool, ghService.getFullCommentsHandler)
+	profiles.addTool(s, getFullCommentsTool, (*githubService).getFullCommentsHandler)
+
+	// 9. Tool to resolve a review thread
+	resolveReviewThreadTool := mcp.NewTool(
+		"resolve_review_thread",
+		mcp.WithDescription("Resolves a review thread on a pull request. Thread IDs are listed by get_full_comments."),
+		mcp.WithString(
+			"thread_id",
+			mcp.Required(),
+			mcp.Description("The GraphQL node ID of the review thread (e.g., PRRT_kwDOABCD1234)"),
+		),
+	)
+
+	profiles.addTool(s, resolveReviewThreadTool, (*githubService).resolveReviewThreadHandler)
+
+	// 10. Tool to reply to an existing review thread
+	replyToReviewCommentTool := mcp.NewTool(
+		"reply_to_review_comment",
+		mcp.WithDescription("Posts a reply to an existing review thread on a pull request. Provide either thread_id or comment_id."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithString(
+			"thread_id",
+			mcp.Description("The GraphQL node ID of the review thread to reply to, as shown by get_full_comments."),
+		),
+		mcp.WithNumber(
+			"comment_id",
+			mcp.Description("The numeric ID of the review comment to reply to. Takes precedence over thread_id."),
+		),
+		mcp.WithString(
+			"body",
+			mcp.Required(),
+			mcp.Description("The text of the reply (Markdown supported)."),
+		),
+	)
+
+	profiles.addTool(s, replyToReviewCommentTool, (*githubService).replyToReviewCommentHandler)
+
+	// 11. Tool to approve a pull request
+	approvePullRequestTool := mcp.NewTool(
+		"approve_pull_request",
+		mcp.WithDescription("Submits an approving review on a pull request."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithString(
+			"body",
+			mcp.Description("Optional comment to include with the approval."),
+		),
+	)
+
+	profiles.addTool(s, approvePullRequestTool, (*githubService).approvePullRequestHandler)
+
+	// 12. Tool to request changes on a pull request
+	requestChangesTool := mcp.NewTool(
+		"request_changes",
+		mcp.WithDescription("Submits a review requesting changes on a pull request."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithString(
+			"body",
+			mcp.Required(),
+			mcp.Description("Explanation of the requested changes. GitHub requires a body for this review type."),
+		),
+	)
+
+	profiles.addTool(s, requestChangesTool, (*githubService).requestChangesHandler)
+
+	// 13. Tool to merge a pull request
+	mergePullRequestTool := mcp.NewTool(
+		"merge_pull_request",
+		mcp.WithDescription("Merges a pull request using the selected merge method."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithString(
+			"merge_method",
+			mcp.Description("The merge method to use (merge, squash, or rebase). Defaults to 'merge'."),
+			mcp.Enum("merge", "squash", "rebase"),
+		),
+		mcp.WithString(
+			"commit_title",
+			mcp.Description("Optional title for the merge commit."),
+		),
+		mcp.WithString(
+			"commit_message",
+			mcp.Description("Optional extra detail to append to the merge commit message."),
+		),
+	)
+
+	profiles.addTool(s, mergePullRequestTool, (*githubService).mergePullRequestHandler)
+
+	// 14. Tool to get the unified diff of a pull request
+	getPRDiffTool := mcp.NewTool(
+		"get_pr_diff",
+		mcp.WithDescription("Gets the unified diff of a pull request."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithNumber(
+			"max_bytes",
+			mcp.Description("Optional maximum size of the returned diff in bytes. Larger diffs are truncated. Defaults to no limit."),
+		),
+	)
+
+	profiles.addTool(s, getPRDiffTool, (*githubService).getPRDiffHandler)
+
+	// 15. Tool to list the files changed by a pull request
+	getPRFilesTool := mcp.NewTool(
+		"get_pr_files",
+		mcp.WithDescription("Lists the files changed by a pull request with their status and line counts."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithBoolean(
+			"include_patch",
+			mcp.Description("If true, include each file's patch hunk. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, getPRFilesTool, (*githubService).getPRFilesHandler)
+
+	// 16. Tool to list the commits of a pull request
+	getPRCommitsTool := mcp.NewTool(
+		"get_pr_commits",
+		mcp.WithDescription("Lists the commits on a pull request."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+	)
+
+	profiles.addTool(s, getPRCommitsTool, (*githubService).getPRCommitsHandler)
+
+	// 17. Tool to get the CI status of a pull request
+	getPRStatusTool := mcp.NewTool(
+		"get_pr_status",
+		mcp.WithDescription("Reports the CI check runs and commit statuses for the head commit of a pull request."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+	)
+
+	profiles.addTool(s, getPRStatusTool, (*githubService).getPRStatusHandler)
+
+	// 18. Tool to create an issue
+	createIssueTool := mcp.NewTool(
+		"create_issue",
+		mcp.WithDescription("Creates a new issue in a repository."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"title",
+			mcp.Required(),
+			mcp.Description("The title of the issue."),
+		),
+		mcp.WithString(
+			"body",
+			mcp.Description("Optional body of the issue (Markdown supported)."),
+		),
+		mcp.WithString(
+			"labels",
+			mcp.Description("Optional comma-separated list of labels to apply (e.g., 'bug,help wanted')."),
+		),
+		mcp.WithString(
+			"assignees",
+			mcp.Description("Optional comma-separated list of GitHub logins to assign."),
+		),
+	)
+
+	profiles.addTool(s, createIssueTool, (*githubService).createIssueHandler)
+
+	// 19. Tool to list the issues of a repository
+	listIssuesTool := mcp.NewTool(
+		"list_issues",
+		mcp.WithDescription("Lists issues in a repository. Pull requests are excluded."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"state",
+			mcp.Description("The state of the issues to list (open, closed, or all). Defaults to 'open'."),
+			mcp.Enum("open", "closed", "all"),
+		),
+		mcp.WithString(
+			"labels",
+			mcp.Description("Optional comma-separated list of labels; only issues with all of them are returned."),
+		),
+		mcp.WithString(
+			"assignee",
+			mcp.Description("Optional GitHub login to filter issues by assignee. Use 'none' for unassigned issues."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of issues to return (1-100). Defaults to 30."),
+		),
+	)
+
+	profiles.addTool(s, listIssuesTool, (*githubService).listIssuesHandler)
+
+	// 20. Tool to comment on an issue
+	commentOnIssueTool := mcp.NewTool(
+		"comment_on_issue",
+		mcp.WithDescription("Adds a comment to an issue. Identify the issue with issue_url, or with repo and number."),
+		mcp.WithString(
+			"issue_url",
+			mcp.Description("The full URL of the issue (e.g., https://github.com/owner/repo/issues/123)"),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("The repository in the form owner/name. Used together with number when issue_url is not given."),
+		),
+		mcp.WithNumber(
+			"number",
+			mcp.Description("The issue number. Used together with repo when issue_url is not given."),
+		),
+		mcp.WithString(
+			"body",
+			mcp.Required(),
+			mcp.Description("The text of the comment (Markdown supported)."),
+		),
+	)
+
+	profiles.addTool(s, commentOnIssueTool, (*githubService).commentOnIssueHandler)
+
+	// 21. Tools to close and reopen an issue
+	closeIssueTool := mcp.NewTool(
+		"close_issue",
+		mcp.WithDescription("Closes an issue. Identify the issue with issue_url, or with repo and number."),
+		mcp.WithString(
+			"issue_url",
+			mcp.Description("The full URL of the issue (e.g., https://github.com/owner/repo/issues/123)"),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("The repository in the form owner/name. Used together with number when issue_url is not given."),
+		),
+		mcp.WithNumber(
+			"number",
+			mcp.Description("The issue number. Used together with repo when issue_url is not given."),
+		),
+		mcp.WithString(
+			"state_reason",
+			mcp.Description("Why the issue is being closed (completed or not_planned). Defaults to 'completed'."),
+			mcp.Enum("completed", "not_planned"),
+		),
+	)
+
+	profiles.addTool(s, closeIssueTool, (*githubService).closeIssueHandler)
+
+	reopenIssueTool := mcp.NewTool(
+		"reopen_issue",
+		mcp.WithDescription("Reopens a closed issue. Identify the issue with issue_url, or with repo and number."),
+		mcp.WithString(
+			"issue_url",
+			mcp.Description("The full URL of the issue (e.g., https://github.com/owner/repo/issues/123)"),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("The repository in the form owner/name. Used together with number when issue_url is not given."),
+		),
+		mcp.WithNumber(
+			"number",
+			mcp.Description("The issue number. Used together with repo when issue_url is not given."),
+		),
+	)
+
+	profiles.addTool(s, reopenIssueTool, (*githubService).reopenIssueHandler)
+
+	// 22. Tool to search code
+	searchCodeTool := mcp.NewTool(
+		"search_code",
+		mcp.WithDescription("Searches code on GitHub. The search must be scoped to a repository, organization, or user."),
+		mcp.WithString(
+			"query",
+			mcp.Required(),
+			mcp.Description("The search terms, optionally with GitHub code search qualifiers (e.g., 'func parsePRURL org:my-org')."),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("Optional repository in the form owner/name to search in."),
+		),
+		mcp.WithString(
+			"language",
+			mcp.Description("Optional language to restrict results to (e.g., go, python)."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of results to return (1-100). Defaults to 20."),
+		),
+	)
+
+	profiles.addTool(s, searchCodeTool, (*githubService).searchCodeHandler)
+
+	// 23. Tool to search issues and pull requests with raw GitHub search syntax
+	searchIssuesTool := mcp.NewTool(
+		"search_issues",
+		mcp.WithDescription("Searches issues and pull requests using GitHub's search syntax (e.g., 'is:issue label:bug repo:owner/name')."),
+		mcp.WithString(
+			"query",
+			mcp.Required(),
+			mcp.Description("The raw GitHub issue search query (see https://docs.github.com/search-github/searching-on-github/searching-issues-and-pull-requests)."),
+		),
+		mcp.WithString(
+			"sort",
+			mcp.Description("Optional sort field. Defaults to best match."),
+			mcp.Enum("comments", "reactions", "created", "updated", "interactions"),
+		),
+		mcp.WithString(
+			"order",
+			mcp.Description("Optional sort order (asc or desc). Defaults to 'desc'."),
+			mcp.Enum("asc", "desc"),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of results to return (1-100). Defaults to 20."),
+		),
+	)
+
+	profiles.addTool(s, searchIssuesTool, (*githubService).searchIssuesHandler)
+
+	// 24. Tool to get an issue with its comments
+	getIssueTool := mcp.NewTool(
+		"get_issue",
+		mcp.WithDescription("Gets an issue with its description, labels, assignees, and all comments. Identify the issue with issue_url, or with repo and number."),
+		mcp.WithString(
+			"issue_url",
+			mcp.Description("The full URL of the issue (e.g., https://github.com/owner/repo/issues/123)"),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("The repository in the form owner/name. Used together with number when issue_url is not given."),
+		),
+		mcp.WithNumber(
+			"number",
+			mcp.Description("The issue number. Used together with repo when issue_url is not given."),
+		),
+	)
+
+	profiles.addTool(s, getIssueTool, (*githubService).getIssueHandler)
+
+	// 25. Tool to report the current API rate limits
+	getRateLimitTool := mcp.NewTool(
+		"get_rate_limit",
+		mcp.WithDescription("Reports the remaining REST (core and search) and GraphQL rate limit budgets for the authenticated user."),
+	)
+
+	profiles.addTool(s, getRateLimitTool, (*githubService).getRateLimitHandler)
+
+	// 26. Tool to summarize the review state of a pull request
+	getReviewSummaryTool := mcp.NewTool(
+		"get_review_summary",
+		mcp.WithDescription("Summarizes each reviewer's latest review state and the number of unresolved threads on a pull request."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+	)
+
+	profiles.addTool(s, getReviewSummaryTool, (*githubService).getReviewSummaryHandler)
+
+	// 27. Tool to leave an inline review comment on a file line
+	createReviewCommentTool := mcp.NewTool(
+		"create_review_comment",
+		mcp.WithDescription("Creates a new inline review comment on a specific line of a file in a pull request."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithString(
+			"path",
+			mcp.Required(),
+			mcp.Description("The path of the file to comment on, relative to the repository root."),
+		),
+		mcp.WithNumber(
+			"line",
+			mcp.Required(),
+			mcp.Description("The line of the file to comment on."),
+		),
+		mcp.WithString(
+			"side",
+			mcp.Description("Which side of the diff the line is on: LEFT (deletions) or RIGHT (additions and context). Defaults to 'RIGHT'."),
+			mcp.Enum("LEFT", "RIGHT"),
+		),
+		mcp.WithString(
+			"body",
+			mcp.Required(),
+			mcp.Description("The text of the comment (Markdown supported)."),
+		),
+	)
+
+	profiles.addTool(s, createReviewCommentTool, (*githubService).createReviewCommentHandler)
+
+	// 28. Tool to list pull requests waiting on the authenticated user's review
+	listMyReviewRequestsTool := mcp.NewTool(
+		"list_my_review_requests",
+		mcp.WithDescription("Lists open pull requests that request a review from the authenticated user, most recently updated first."),
+		mcp.WithString(
+			"repo",
+			mcp.Description("Optional repository in the form owner/name to scope the listing to."),
+		),
+		mcp.WithString(
+			"exclude_author",
+			mcp.Description("Optional GitHub login whose pull requests should be left out."),
+		),
+		mcp.WithBoolean(
+			"exclude_own",
+			mcp.Description("If true, leave out pull requests authored by the authenticated user, which team review requests can include. Defaults to false."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of pull requests to return (1-100). Defaults to 30."),
+		),
+	)
+
+	profiles.addTool(s, listMyReviewRequestsTool, (*githubService).listMyReviewRequestsHandler)
+
+	// 29. Tool to list review threads grouped by file
+	getCommentsByFileTool := mcp.NewTool(
+		"get_comments_by_file",
+		mcp.WithDescription("Gets review threads from a pull request grouped by file, with files that have the most unresolved threads first."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithBoolean(
+			"unresolved_only",
+			mcp.Description("If true, only show unresolved threads. If false, show all threads. Defaults to false."),
+		),
+		mcp.WithBoolean(
+			"no_cache",
+			mcp.Description("If true, bypass the short-lived cache of review threads and fetch fresh data. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, getCommentsByFileTool, (*githubService).getCommentsByFileHandler)
+
+	// 30. Tool to report server version and connectivity diagnostics
+	serverInfoTool := mcp.NewTool(
+		"server_info",
+		mcp.WithDescription("Reports the server version, configured GitHub API URL, authenticated user, and whether REST and GraphQL connectivity work."),
+	)
+
+	profiles.addTool(s, serverInfoTool, (*githubService).serverInfoHandler)
+
+	// 31. Tool to read a file from a repository
+	getFileContentsTool := mcp.NewTool(
+		"get_file_contents",
+		mcp.WithDescription("Reads a file from a repository. For a directory, lists its entries instead."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"path",
+			mcp.Required(),
+			mcp.Description("The path of the file or directory, relative to the repository root."),
+		),
+		mcp.WithString(
+			"ref",
+			mcp.Description("Optional branch, tag, or commit SHA. Defaults to the repository's default branch."),
+		),
+	)
+
+	profiles.addTool(s, getFileContentsTool, (*githubService).getFileContentsHandler)
+
+	// 32. Tool to list the branches of a repository
+	listBranchesTool := mcp.NewTool(
+		"list_branches",
+		mcp.WithDescription("Lists the branches of a repository and whether each is protected."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"prefix",
+			mcp.Description("Optional prefix; only branches whose names start with it are returned (e.g., 'feature/')."),
+		),
+	)
+
+	profiles.addTool(s, listBranchesTool, (*githubService).listBranchesHandler)
+
+	// 33. Tool to get a single commit
+	getCommitTool := mcp.NewTool(
+		"get_commit",
+		mcp.WithDescription("Gets a commit's author, date, message, and per-file change summary."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"sha",
+			mcp.Required(),
+			mcp.Description("The full or abbreviated commit SHA."),
+		),
+		mcp.WithBoolean(
+			"include_patch",
+			mcp.Description("If true, include each file's patch hunk. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, getCommitTool, (*githubService).getCommitHandler)
+
+	// 34. Tool to list GitHub Actions workflow runs
+	listWorkflowRunsTool := mcp.NewTool(
+		"list_workflow_runs",
+		mcp.WithDescription("Lists GitHub Actions workflow runs for a repository, most recent first."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("Optional branch to filter runs by."),
+		),
+		mcp.WithString(
+			"status",
+			mcp.Description("Optional status or conclusion to filter runs by (e.g., in_progress, completed, failure, success)."),
+		),
+		mcp.WithString(
+			"event",
+			mcp.Description("Optional triggering event to filter runs by (e.g., push, pull_request)."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of runs to return (1-100). Defaults to 20."),
+		),
+	)
+
+	profiles.addTool(s, listWorkflowRunsTool, (*githubService).listWorkflowRunsHandler)
+
+	// 35. Tool to re-run the failed jobs of a workflow run
+	rerunFailedJobsTool := mcp.NewTool(
+		"rerun_failed_jobs",
+		mcp.WithDescription("Re-runs the failed jobs of a completed GitHub Actions workflow run."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithNumber(
+			"run_id",
+			mcp.Required(),
+			mcp.Description("The ID of the workflow run, as shown by list_workflow_runs."),
+		),
+	)
+
+	profiles.addTool(s, rerunFailedJobsTool, (*githubService).rerunFailedJobsHandler)
+
+	// 36. Tools to list releases and get the latest release
+	listReleasesTool := mcp.NewTool(
+		"list_releases",
+		mcp.WithDescription("Lists the releases of a repository, newest first."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of releases to show (1-100). Defaults to 10."),
+		),
+		mcp.WithBoolean(
+			"full_notes",
+			mcp.Description("If true, show complete release notes instead of a truncated preview. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, listReleasesTool, (*githubService).listReleasesHandler)
+
+	getLatestReleaseTool := mcp.NewTool(
+		"get_latest_release",
+		mcp.WithDescription("Gets the latest published, non-prerelease release of a repository."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithBoolean(
+			"full_notes",
+			mcp.Description("If true, show complete release notes instead of a truncated preview. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, getLatestReleaseTool, (*githubService).getLatestReleaseHandler)
+
+	// 37. Tool to list the authenticated user's notifications
+	getNotificationsTool := mcp.NewTool(
+		"get_notifications",
+		mcp.WithDescription("Lists the authenticated user's GitHub notifications. By default only unread notifications are shown."),
+		mcp.WithBoolean(
+			"all",
+			mcp.Description("If true, include notifications already marked as read. Defaults to false."),
+		),
+		mcp.WithBoolean(
+			"participating",
+			mcp.Description("If true, only show notifications where the user is directly participating or mentioned. Defaults to false."),
+		),
+		mcp.WithString(
+			"since",
+			mcp.Description("Optional time (YYYY-MM-DD or RFC 3339); only notifications updated after it are shown."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of notifications to return (1-100). Defaults to 30."),
+		),
+	)
+
+	profiles.addTool(s, getNotificationsTool, (*githubService).getNotificationsHandler)
+
+	// 38. Tool to mark notifications as read
+	markNotificationReadTool := mcp.NewTool(
+		"mark_notification_read",
+		mcp.WithDescription("Marks a single notification thread as read, or all notifications when mark_all is set."),
+		mcp.WithString(
+			"thread_id",
+			mcp.Description("The notification thread ID, as shown by get_notifications."),
+		),
+		mcp.WithBoolean(
+			"mark_all",
+			mcp.Description("If true, mark every notification updated before last_read_at as read. Defaults to false."),
+		),
+		mcp.WithString(
+			"last_read_at",
+			mcp.Description("Used with mark_all: notifications updated before this time (YYYY-MM-DD or RFC 3339) are marked as read. Defaults to now."),
+		),
+	)
+
+	profiles.addTool(s, markNotificationReadTool, (*githubService).markNotificationReadHandler)
+
+	// 39. Tool to add labels to an issue or pull request
+	addLabelsToIssueTool := mcp.NewTool(
+		"add_labels_to_issue",
+		mcp.WithDescription("Adds existing repository labels to an issue or pull request and returns its resulting labels. Identify the issue with issue_url, or with repo and number."),
+		mcp.WithString(
+			"issue_url",
+			mcp.Description("The full URL of the issue or pull request (e.g., https://github.com/owner/repo/issues/123)"),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("The repository in the form owner/name. Used together with number when issue_url is not given."),
+		),
+		mcp.WithNumber(
+			"number",
+			mcp.Description("The issue or pull request number. Used together with repo when issue_url is not given."),
+		),
+		mcp.WithString(
+			"labels",
+			mcp.Required(),
+			mcp.Description("Comma-separated label names to add (e.g., \"bug,needs triage\"). Each must already exist in the repository."),
+		),
+	)
+
+	profiles.addTool(s, addLabelsToIssueTool, (*githubService).addLabelsToIssueHandler)
+
+	// 40. Tool to remove a label from an issue or pull request
+	removeLabelFromIssueTool := mcp.NewTool(
+		"remove_label_from_issue",
+		mcp.WithDescription("Removes a label from an issue or pull request and returns its remaining labels. Identify the issue with issue_url, or with repo and number."),
+		mcp.WithString(
+			"issue_url",
+			mcp.Description("The full URL of the issue or pull request (e.g., https://github.com/owner/repo/issues/123)"),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("The repository in the form owner/name. Used together with number when issue_url is not given."),
+		),
+		mcp.WithNumber(
+			"number",
+			mcp.Description("The issue or pull request number. Used together with repo when issue_url is not given."),
+		),
+		mcp.WithString(
+			"label",
+			mcp.Required(),
+			mcp.Description("The label name to remove"),
+		),
+	)
+
+	profiles.addTool(s, removeLabelFromIssueTool, (*githubService).removeLabelFromIssueHandler)
+
+	// 41. Tool to request reviewers on a pull request
+	requestReviewersTool := mcp.NewTool(
+		"request_reviewers",
+		mcp.WithDescription("Requests reviews on a pull request from users and/or teams, and returns the resulting list of pending reviewers."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithString(
+			"reviewers",
+			mcp.Description("Comma-separated GitHub logins to request reviews from (e.g., \"octocat,hubot\")"),
+		),
+		mcp.WithString(
+			"team_reviewers",
+			mcp.Description("Comma-separated team slugs in the repository's organization to request reviews from"),
+		),
+	)
+
+	profiles.addTool(s, requestReviewersTool, (*githubService).requestReviewersHandler)
+
+	// 42. Tool to show who resolved each review thread
+	getThreadStatusTool := mcp.NewTool(
+		"get_thread_status",
+		mcp.WithDescription("Lists every review thread on a pull request with its resolution status and who resolved it. GitHub does not record when a thread was resolved; the latest comment time is shown instead."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithBoolean(
+			"relative_time",
+			mcp.Description("If true, show comment times relative to now (e.g., '3 days ago') instead of UTC timestamps. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, getThreadStatusTool, (*githubService).getThreadStatusHandler)
+
+	// 43. Tool to close a pull request without merging
+	closePullRequestTool := mcp.NewTool(
+		"close_pull_request",
+		mcp.WithDescription("Closes a pull request without merging it. Merged pull requests cannot be closed."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+	)
+
+	profiles.addTool(s, closePullRequestTool, (*githubService).closePullRequestHandler)
+
+	// 44. Tool to reopen a closed pull request
+	reopenPullRequestTool := mcp.NewTool(
+		"reopen_pull_request",
+		mcp.WithDescription("Reopens a closed, unmerged pull request."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+	)
+
+	profiles.addTool(s, reopenPullRequestTool, (*githubService).reopenPullRequestHandler)
+
+	// 45. Tool to open a pull request
+	createPullRequestTool := mcp.NewTool(
+		"create_pull_request",
+		mcp.WithDescription("Opens a pull request from a head branch into a base branch and returns its number and URL."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"title",
+			mcp.Required(),
+			mcp.Description("The pull request title"),
+		),
+		mcp.WithString(
+			"head",
+			mcp.Required(),
+			mcp.Description("The branch with the changes. Use owner:branch for a branch in a fork."),
+		),
+		mcp.WithString(
+			"base",
+			mcp.Required(),
+			mcp.Description("The branch to merge the changes into (e.g., main)"),
+		),
+		mcp.WithString(
+			"body",
+			mcp.Description("Optional pull request description (Markdown)"),
+		),
+		mcp.WithBoolean(
+			"draft",
+			mcp.Description("If true, open the pull request as a draft. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, createPullRequestTool, (*githubService).createPullRequestHandler)
+
+	// 46. Tool to convert a pull request to a draft
+	convertToDraftTool := mcp.NewTool(
+		"convert_to_draft",
+		mcp.WithDescription("Converts an open pull request back to a draft."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+	)
+
+	profiles.addTool(s, convertToDraftTool, (*githubService).convertToDraftHandler)
+
+	// 47. Tool to mark a draft pull request ready for review
+	readyForReviewTool := mcp.NewTool(
+		"ready_for_review",
+		mcp.WithDescription("Marks a draft pull request as ready for review."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+	)
+
+	profiles.addTool(s, readyForReviewTool, (*githubService).readyForReviewHandler)
+
+	// 48. Tool to summarize a pull request in one report
+	getPROverviewTool := mcp.NewTool(
+		"get_pr_overview",
+		mcp.WithDescription("Gets a one-screen overview of a pull request: title, author, branches, mergeable state, CI check summary, and unresolved review thread count."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithBoolean(
+			"no_cache",
+			mcp.Description("If true, bypass the short-lived cache of review threads and fetch fresh data. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, getPROverviewTool, (*githubService).getPROverviewHandler)
+
+	// 49. Tool to get review comments posted after a point in time
+	getReviewCommentsSinceTool := mcp.NewTool(
+		"get_pr_review_comments_since",
+		mcp.WithDescription("Gets only the review comments on a pull request created after a given time, grouped by thread. Threads without newer comments are left out."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithString(
+			"since",
+			mcp.Required(),
+			mcp.Description("Only include comments created after this time, as YYYY-MM-DD or RFC 3339 (e.g., 2024-05-01T14:03:00Z)."),
+		),
+		mcp.WithBoolean(
+			"relative_time",
+			mcp.Description("If true, show comment times relative to now (e.g., '3 days ago') instead of UTC timestamps. Defaults to false."),
+		),
+		mcp.WithBoolean(
+			"no_cache",
+			mcp.Description("If true, bypass the short-lived cache of review threads and fetch fresh data. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, getReviewCommentsSinceTool, (*githubService).getReviewCommentsSinceHandler)
+
+	// 50. Tool to edit an existing review comment
+	editReviewCommentTool := mcp.NewTool(
+		"edit_review_comment",
+		mcp.WithDescription("Replaces the body of an existing pull request review comment. Comment IDs are shown by get_full_comments."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithNumber(
+			"comment_id",
+			mcp.Required(),
+			mcp.Description("The numeric ID of the review comment to edit"),
+		),
+		mcp.WithString(
+			"body",
+			mcp.Required(),
+			mcp.Description("The new comment body (Markdown)"),
+		),
+	)
+
+	profiles.addTool(s, editReviewCommentTool, (*githubService).editReviewCommentHandler)
+
+	// 51. Tool to delete a review comment
+	deleteReviewCommentTool := mcp.NewTool(
+		"delete_review_comment",
+		mcp.WithDescription("Permanently deletes a pull request review comment. Requires confirm to be true."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithNumber(
+			"comment_id",
+			mcp.Required(),
+			mcp.Description("The numeric ID of the review comment to delete, as shown by get_full_comments"),
+		),
+		mcp.WithBoolean(
+			"confirm",
+			mcp.Required(),
+			mcp.Description("Must be true to delete the comment. Deletion cannot be undone."),
+		),
+	)
+
+	profiles.addTool(s, deleteReviewCommentTool, (*githubService).deleteReviewCommentHandler)
+
+	// 52. Prompt to summarize a pull request for review
+	summarizePRPrompt := mcp.NewPrompt(
+		"summarize_pr",
+		mcp.WithPromptDescription("Asks for a review summary of a pull request, pre-filled with its description, diff and unresolved review comments."),
+		mcp.WithArgument(
+			"pull_request_url",
+			mcp.ArgumentDescription("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+			mcp.RequiredArgument(),
+		),
+	)
+
+	s.AddPrompt(summarizePRPrompt, profiles.primaryService().summarizePRPromptHandler)
+
+	// 53. Resource exposing a pull request and its unresolved comments
+	pullRequestResource := mcp.NewResourceTemplate(
+		"github://pr/{owner}/{repo}/{number}",
+		"Pull request",
+		mcp.WithTemplateDescription("A pull request's description and unresolved review threads, as Markdown."),
+		mcp.WithTemplateMIMEType("text/markdown"),
+	)
+
+	s.AddResourceTemplate(pullRequestResource, profiles.primaryService().pullRequestResourceHandler)
+
+	// 54. Tool to read the end of a CI job's log
+	getCheckRunLogsTool := mcp.NewTool(
+		"get_check_run_logs",
+		mcp.WithDescription("Gets the last lines of the log of a GitHub Actions job, where the failure is usually reported. Takes the check run ID shown for Actions checks, or the job ID."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithNumber(
+			"check_run_id",
+			mcp.Description("The check run ID of a GitHub Actions check; identical to its job ID."),
+		),
+		mcp.WithNumber(
+			"job_id",
+			mcp.Description("The workflow job ID. Used when check_run_id is not given."),
+		),
+		mcp.WithNumber(
+			"lines",
+			mcp.Description("Number of lines to return from the end of the log (1-1000). Defaults to 100."),
+		),
+	)
+
+	profiles.addTool(s, getCheckRunLogsTool, (*githubService).getCheckRunLogsHandler)
+
+	// 55. Tool to dismiss a pull request review
+	dismissReviewTool := mcp.NewTool(
+		"dismiss_review",
+		mcp.WithDescription("Dismisses a submitted pull request review, such as a stale change request whose concerns have been addressed. Review IDs are shown by get_review_summary."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithNumber(
+			"review_id",
+			mcp.Required(),
+			mcp.Description("The numeric ID of the review to dismiss"),
+		),
+		mcp.WithString(
+			"message",
+			mcp.Required(),
+			mcp.Description("Why the review is being dismissed; shown on the pull request"),
+		),
+	)
+
+	profiles.addTool(s, dismissReviewTool, (*githubService).dismissReviewHandler)
+
+	// 56. Tool to list unresolved threads on outdated code
+	getStaleThreadsTool := mcp.NewTool(
+		"get_stale_threads",
+		mcp.WithDescription("Lists unresolved review threads whose code has changed since the comment was left, showing the file, original line, and a preview of the first comment. Helps decide which feedback to revisit and which to resolve."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithBoolean(
+			"no_cache",
+			mcp.Description("If true, bypass the short-lived cache of review threads and fetch fresh data. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, getStaleThreadsTool, (*githubService).getStaleThreadsHandler)
+
+	// 57. Tool to sweep several pull requests for unresolved comments
+	getCommentsForPRsTool := mcp.NewTool(
+		"get_comments_for_prs",
+		mcp.WithDescription("Gets the unresolved review threads of several pull requests at once, with one section per pull request. Errors are reported per pull request."),
+		mcp.WithString(
+			"pull_request_urls",
+			mcp.Required(),
+			mcp.Description("Pull request URLs, comma-separated or as a JSON array of strings"),
+		),
+		mcp.WithBoolean(
+			"no_cache",
+			mcp.Description("If true, bypass the short-lived cache of review threads and fetch fresh data. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, getCommentsForPRsTool, (*githubService).getCommentsForPRsHandler)
+
+	// 58. Tool to list open issues and pull requests that mention the user
+	getMyMentionsTool := mcp.NewTool(
+		"get_my_mentions",
+		mcp.WithDescription("Lists open issues and pull requests where the authenticated user is @-mentioned, most recently updated first."),
+		mcp.WithString(
+			"repo",
+			mcp.Description("Optional repository in the form owner/name to scope the listing to."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of results to return (1-100). Defaults to 30."),
+		),
+	)
+
+	profiles.addTool(s, getMyMentionsTool, (*githubService).getMyMentionsHandler)
+
+	// 59. Tool to share snippets as a gist
+	createGistTool := mcp.NewTool(
+		"create_gist",
+		mcp.WithDescription("Creates a gist from one or more files and returns its URL and the raw URL of each file. Gists are secret unless public is set."),
+		mcp.WithString(
+			"filename",
+			mcp.Description("Name of the file, including its extension (e.g., snippet.go). Used with content for a single-file gist."),
+		),
+		mcp.WithString(
+			"content",
+			mcp.Description("Contents of the file named by filename"),
+		),
+		mcp.WithString(
+			"files",
+			mcp.Description("For multiple files: a JSON object mapping filenames to contents (e.g., {\"a.go\": \"...\", \"b.go\": \"...\"})"),
+		),
+		mcp.WithString(
+			"description",
+			mcp.Description("Optional description of the gist"),
+		),
+		mcp.WithBoolean(
+			"public",
+			mcp.Description("If true, create a public gist. Defaults to false (secret)."),
+		),
+	)
+
+	profiles.addTool(s, createGistTool, (*githubService).createGistHandler)
+
+	// 60. Tool to compare two branches, tags or commits
+	compareCommitsTool := mcp.NewTool(
+		"compare_commits",
+		mcp.WithDescription("Compares two refs in a repository, showing how far head is ahead of and behind base, the commits between them, and a summary of changed files."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"base",
+			mcp.Required(),
+			mcp.Description("The base branch, tag or commit SHA (e.g., main)"),
+		),
+		mcp.WithString(
+			"head",
+			mcp.Required(),
+			mcp.Description("The head branch, tag or commit SHA to compare against base. Use owner:branch for a fork."),
+		),
+		mcp.WithBoolean(
+			"include_patch",
+			mcp.Description("If true, include each file's patch. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, compareCommitsTool, (*githubService).compareCommitsHandler)
+
+	// 61. Tool to get a pull request's metadata
+	getPRDetailsTool := mcp.NewTool(
+		"get_pr_details",
+		mcp.WithDescription("Gets a pull request's metadata: title, description, author, branches, draft status, mergeable state, labels, assignees, requested reviewers, and milestone."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+	)
+
+	profiles.addTool(s, getPRDetailsTool, (*githubService).getPRDetailsHandler)
+
+	// 62. Tool to resolve every thread the authenticated user started
+	resolveMyThreadsTool := mcp.NewTool(
+		"resolve_my_threads",
+		mcp.WithDescription("Resolves all unresolved review threads on a pull request whose first comment was written by the authenticated user, reporting the outcome for each thread."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+	)
+
+	profiles.addTool(s, resolveMyThreadsTool, (*githubService).resolveMyThreadsHandler)
+
+	// 63. Tool to list repositories of the authenticated user or an organization
+	listRepositoriesTool := mcp.NewTool(
+		"list_repositories",
+		mcp.WithDescription("Lists repositories the authenticated user can access, or the repositories of an organization, with visibility, default branch, and URL."),
+		mcp.WithString(
+			"org",
+			mcp.Description("Optional organization login. If omitted, lists repositories of the authenticated user."),
+		),
+		mcp.WithString(
+			"sort",
+			mcp.Description("Sort by most recently updated or pushed. Defaults to 'updated'."),
+			mcp.Enum("updated", "pushed"),
+		),
+		mcp.WithString(
+			"name_contains",
+			mcp.Description("Optional case-insensitive substring; only repositories whose names contain it are returned."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of repositories to return (1-100). Defaults to 30."),
+		),
+	)
+
+	profiles.addTool(s, listRepositoriesTool, (*githubService).listRepositoriesHandler)
+
+	// 64. Tool to list the jobs of a workflow run
+	getWorkflowRunJobsTool := mcp.NewTool(
+		"get_workflow_run_jobs",
+		mcp.WithDescription("Lists the jobs of a GitHub Actions workflow run with their status, conclusion, and the step that failed, if any."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithNumber(
+			"run_id",
+			mcp.Required(),
+			mcp.Description("The ID of the workflow run, as shown by list_workflow_runs."),
+		),
+	)
+
+	profiles.addTool(s, getWorkflowRunJobsTool, (*githubService).getWorkflowRunJobsHandler)
+
+	// 65. Tool to cancel an in-progress workflow run
+	cancelWorkflowRunTool := mcp.NewTool(
+		"cancel_workflow_run",
+		mcp.WithDescription("Cancels a queued or in-progress GitHub Actions workflow run."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithNumber(
+			"run_id",
+			mcp.Required(),
+			mcp.Description("The ID of the workflow run, as shown by list_workflow_runs."),
+		),
+	)
+
+	profiles.addTool(s, cancelWorkflowRunTool, (*githubService).cancelWorkflowRunHandler)
+
+	// 66. Tool to create a branch from an existing ref
+	createBranchTool := mcp.NewTool(
+		"create_branch",
+		mcp.WithDescription("Creates a new branch in a repository, starting from an existing branch, tag, or commit SHA."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Required(),
+			mcp.Description("The name of the branch to create (e.g., 'feature/login-fix')."),
+		),
+		mcp.WithString(
+			"from_ref",
+			mcp.Required(),
+			mcp.Description("The branch, tag, or commit SHA the new branch starts from."),
+		),
+	)
+
+	profiles.addTool(s, createBranchTool, (*githubService).createBranchHandler)
+
+	// 67. Tool to create or update a file with a commit
+	createOrUpdateFileTool := mcp.NewTool(
+		"create_or_update_file",
+		mcp.WithDescription("Creates a file, or replaces the contents of an existing one, in a single commit. Commits to the default branch are refused unless allow_default is set."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"path",
+			mcp.Required(),
+			mcp.Description("The path of the file, relative to the repository root."),
+		),
+		mcp.WithString(
+			"content",
+			mcp.Required(),
+			mcp.Description("The complete new contents of the file."),
+		),
+		mcp.WithString(
+			"message",
+			mcp.Required(),
+			mcp.Description("The commit message."),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("The branch to commit to. Defaults to the repository's default branch, which also requires allow_default."),
+		),
+		mcp.WithBoolean(
+			"allow_default",
+			mcp.Description("If true, allow committing directly to the default branch. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, createOrUpdateFileTool, (*githubService).createOrUpdateFileHandler)
+
+	// 68. Tool to get review threads as JSON for automation
+	getThreadsStructuredTool := mcp.NewTool(
+		"get_threads_structured",
+		mcp.WithDescription("Gets the review threads of a pull request as JSON with a stable schema: "+
+			`{"total": int, "threads": [{"id": string, "isResolved": bool, "isOutdated": bool, "path": string, "line": int, "url": string, `+
+			`"comments": [{"id": int, "author": string, "body": string, "createdAt": RFC 3339 timestamp, "url": string}]}]}. `+
+			"Intended for tooling that parses thread state; use get_full_comments for readable output."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithBoolean(
+			"unresolved_only",
+			mcp.Description("If true, only include unresolved threads. Defaults to false."),
+		),
+		mcp.WithBoolean(
+			"no_cache",
+			mcp.Description("If true, bypass the short-lived cache of review threads and fetch fresh data. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, getThreadsStructuredTool, (*githubService).getThreadsStructuredHandler)
+
+	// 69. Tool to re-request a review after pushing changes
+	reRequestReviewTool := mcp.NewTool(
+		"re_request_review",
+		mcp.WithDescription("Re-requests a review from a user on a pull request so they are notified again, for example after pushing fixes. A pending request for the same user is removed and re-added. Returns the resulting list of pending reviewers."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithString(
+			"reviewer",
+			mcp.Required(),
+			mcp.Description("The GitHub login of the reviewer to notify again."),
+		),
+	)
+
+	profiles.addTool(s, reRequestReviewTool, (*githubService).reRequestReviewHandler)
+
+	// 70. Tool to search comments written by the authenticated user
+	searchMyCommentsTool := mcp.NewTool(
+		"search_my_comments",
+		mcp.WithDescription("Searches issue and pull request comments written by the authenticated user for a word or phrase, returning each match with surrounding context and a link."),
+		mcp.WithString(
+			"query",
+			mcp.Required(),
+			mcp.Description("The word or phrase to look for (case-insensitive), e.g. 'migration'."),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("Optional repository in the form owner/name. If omitted, searches all repositories."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of comments to return (1-100). Defaults to 20."),
+		),
+	)
+
+	profiles.addTool(s, searchMyCommentsTool, (*githubService).searchMyCommentsHandler)
+
+	// 71. Tools to list milestones and assign one to an issue or pull request
+	listMilestonesTool := mcp.NewTool(
+		"list_milestones",
+		mcp.WithDescription("Lists the milestones of a repository with their due dates and open and closed issue counts, soonest due first."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"state",
+			mcp.Description("The state of the milestones to list (open, closed, or all). Defaults to 'open'."),
+			mcp.Enum("open", "closed", "all"),
+		),
+	)
+
+	profiles.addTool(s, listMilestonesTool, (*githubService).listMilestonesHandler)
+
+	setMilestoneTool := mcp.NewTool(
+		"set_milestone",
+		mcp.WithDescription("Assigns an existing milestone to an issue or pull request. Identify the issue with issue_url, or with repo and number."),
+		mcp.WithString(
+			"issue_url",
+			mcp.Description("The full URL of the issue or pull request (e.g., https://github.com/owner/repo/issues/123)"),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("The repository in the form owner/name. Used together with number when issue_url is not given."),
+		),
+		mcp.WithNumber(
+			"number",
+			mcp.Description("The issue or pull request number. Used together with repo when issue_url is not given."),
+		),
+		mcp.WithString(
+			"milestone",
+			mcp.Required(),
+			mcp.Description("The milestone title (case-insensitive) or number. It must already exist in the repository."),
+		),
+	)
+
+	profiles.addTool(s, setMilestoneTool, (*githubService).setMilestoneHandler)
+
+	// 72. Tool to list the open pull requests across an organization
+	listOrgOpenPRsTool := mcp.NewTool(
+		"list_org_open_prs",
+		mcp.WithDescription("Lists open pull requests across all repositories of an organization as a compact table, least recently updated first, as a review queue."),
+		mcp.WithString(
+			"org",
+			mcp.Required(),
+			mcp.Description("The organization login (e.g., 'my-org')."),
+		),
+		mcp.WithString(
+			"author",
+			mcp.Description("Optional GitHub login; only pull requests opened by this user are listed."),
+		),
+		mcp.WithString(
+			"label",
+			mcp.Description("Optional label name; only pull requests with this label are listed."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of pull requests to return (1-100). Defaults to 30."),
+		),
+	)
+
+	profiles.addTool(s, listOrgOpenPRsTool, (*githubService).listOrgOpenPRsHandler)
+
+	// 73. Tool to get the review thread a comment link points to
+	getThreadByCommentURLTool := mcp.NewTool(
+		"get_thread_by_comment_url",
+		mcp.WithDescription("Gets the full conversation of the review thread containing a single review comment, given a link to that comment. The linked comment is marked with »."),
+		mcp.WithString(
+			"comment_url",
+			mcp.Required(),
+			mcp.Description("A link to a review comment (e.g., https://github.com/owner/repo/pull/123#discussion_r456)"),
+		),
+		mcp.WithBoolean(
+			"no_cache",
+			mcp.Description("If true, bypass the short-lived cache of review threads and fetch fresh data. Defaults to false."),
+		),
+		mcp.WithBoolean(
+			"relative_time",
+			mcp.Description("If true, show comment times relative to now (e.g., '3 days ago') instead of UTC timestamps. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, getThreadByCommentURLTool, (*githubService).getThreadByCommentURLHandler)
+
+	// 74. Tools to lock and unlock the conversation on an issue or pull request
+	lockConversationTool := mcp.NewTool(
+		"lock_conversation",
+		mcp.WithDescription("Locks the conversation on an issue or pull request so only collaborators can comment. Identify the issue with issue_url, or with repo and number."),
+		mcp.WithString(
+			"issue_url",
+			mcp.Description("The full URL of the issue or pull request (e.g., https://github.com/owner/repo/issues/123)"),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("The repository in the form owner/name. Used together with number when issue_url is not given."),
+		),
+		mcp.WithNumber(
+			"number",
+			mcp.Description("The issue or pull request number. Used together with repo when issue_url is not given."),
+		),
+		mcp.WithString(
+			"reason",
+			mcp.Description("Optional reason shown on the issue for locking it."),
+			mcp.Enum("off-topic", "too heated", "resolved", "spam"),
+		),
+	)
+
+	profiles.addTool(s, lockConversationTool, (*githubService).lockConversationHandler)
+
+	unlockConversationTool := mcp.NewTool(
+		"unlock_conversation",
+		mcp.WithDescription("Unlocks the conversation on an issue or pull request. Identify the issue with issue_url, or with repo and number."),
+		mcp.WithString(
+			"issue_url",
+			mcp.Description("The full URL of the issue or pull request (e.g., https://github.com/owner/repo/issues/123)"),
+		),
+		mcp.WithString(
+			"repo",
+			mcp.Description("The repository in the form owner/name. Used together with number when issue_url is not given."),
+		),
+		mcp.WithNumber(
+			"number",
+			mcp.Description("The issue or pull request number. Used together with repo when issue_url is not given."),
+		),
+	)
+
+	profiles.addTool(s, unlockConversationTool, (*githubService).unlockConversationHandler)
+
+	// 75. Tools to batch inline comments into a single review
+	startReviewTool := mcp.NewTool(
+		"start_review",
+		mcp.WithDescription("Starts a draft review of a pull request. Comments added with add_review_comment_to_batch are held until submit_review posts them together as one review, sending a single notification."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithBoolean(
+			"replace",
+			mcp.Description("If true, discard a draft review already in progress on this pull request and start over. Defaults to false."),
+		),
+	)
+
+	profiles.addTool(s, startReviewTool, (*githubService).startReviewHandler)
+
+	addReviewCommentToBatchTool := mcp.NewTool(
+		"add_review_comment_to_batch",
+		mcp.WithDescription("Adds an inline comment to the draft review started with start_review. Nothing is posted until submit_review."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithString(
+			"path",
+			mcp.Required(),
+			mcp.Description("The path of the file to comment on, relative to the repository root."),
+		),
+		mcp.WithNumber(
+			"line",
+			mcp.Required(),
+			mcp.Description("The line of the file to comment on."),
+		),
+		mcp.WithString(
+			"side",
+			mcp.Description("Which side of the diff the line is on: LEFT (deletions) or RIGHT (additions and context). Defaults to 'RIGHT'."),
+			mcp.Enum("LEFT", "RIGHT"),
+		),
+		mcp.WithString(
+			"body",
+			mcp.Required(),
+			mcp.Description("The text of the comment (Markdown supported)."),
+		),
+	)
+
+	profiles.addTool(s, addReviewCommentToBatchTool, (*githubService).addReviewCommentToBatchHandler)
+
+	submitReviewTool := mcp.NewTool(
+		"submit_review",
+		mcp.WithDescription("Submits the draft review started with start_review, posting all of its comments as a single review."),
+		mcp.WithString(
+			"pull_request_url",
+			mcp.Required(),
+			mcp.Description("The full URL of the pull request (e.g., https://github.com/owner/repo/pull/123)"),
+		),
+		mcp.WithString(
+			"event",
+			mcp.Description("The review verdict: COMMENT, APPROVE, or REQUEST_CHANGES. Defaults to 'COMMENT'."),
+			mcp.Enum("COMMENT", "APPROVE", "REQUEST_CHANGES"),
+		),
+		mcp.WithString(
+			"body",
+			mcp.Description("Optional overall review message (Markdown supported). Required for REQUEST_CHANGES."),
+		),
+	)
+
+	profiles.addTool(s, submitReviewTool, (*githubService).submitReviewHandler)
+
+	// 76. Tool to find the commit that last changed a line
+	getBlameTool := mcp.NewTool(
+		"get_blame",
+		mcp.WithDescription("Shows the commit, author, and date that last changed a line of a file, e.g. to attribute code discussed in a review comment."),
+		mcp.WithString(
+			"repo",
+			mcp.Required(),
+			mcp.Description("The repository in the form owner/name."),
+		),
+		mcp.WithString(
+			"path",
+			mcp.Required(),
+			mcp.Description("The path of the file, relative to the repository root."),
+		),
+		mcp.WithNumber(
+			"line",
+			mcp.Required(),
+			mcp.Description("The line number to look up."),
+		),
+		mcp.WithString(
+			"ref",
+			mcp.Description("Optional branch, tag, or commit SHA. Defaults to the repository's default branch."),
+		),
+	)
+
+	profiles.addTool(s, getBlameTool, (*githubService).getBlameHandler)
+
+	// Cancel the root context on SIGINT or SIGTERM so the transport can stop
+	// accepting requests and finish the ones in flight.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	log.Println("MCP server running. Waiting for requests from Claude CLI...")
-	if err := server.ServeStdio(s); err != nil {
-		log.Fatalf("Server failed to run: %v", err)
+	if err := serve(ctx, s); err != nil {
+		slog.Error("Server failed to run", "error", redactSecrets(err.Error()))
+		os.Exit(1)
 	}
+	slog.Info("MCP server stopped")
 }
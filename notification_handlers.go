@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// apiRepoURLRegex matches REST API URLs on github.com (api.github.com/repos/...)
+// and GitHub Enterprise Server (<host>/api/v3/repos/...).
+var apiRepoURLRegex = regexp.MustCompile(`^(https?://)(?:api\.)?([^/]+)(?:/api/v3)?/repos/(.+)$`)
+
+// apiToHTMLURL converts a notification subject's API URL into the matching
+// web URL for issues, pull requests, and commits. Other subjects (such as
+// releases, whose API URLs use numeric IDs) fall back to fallback.
+func apiToHTMLURL(apiURL, fallback string) string {
+	matches := apiRepoURLRegex.FindStringSubmatch(apiURL)
+	if len(matches) != 4 {
+		return fallback
+	}
+
+	path := matches[3]
+	switch {
+	case strings.Contains(path, "/pulls/"):
+		path = strings.Replace(path, "/pulls/", "/pull/", 1)
+	case strings.Contains(path, "/issues/"), strings.Contains(path, "/commits/"):
+	default:
+		return fallback
+	}
+
+	return matches[1] + matches[2] + "/" + path
+}
+
+// parseSince accepts either an RFC 3339 timestamp or a plain YYYY-MM-DD date.
+func parseSince(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q. Expected YYYY-MM-DD or RFC 3339 (e.g., 2024-05-01T14:03:00Z)", value)
+}
+
+func (s *githubService) getNotificationsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts := &github.NotificationListOptions{
+		All:           req.GetBool("all", false),
+		Participating: req.GetBool("participating", false),
+		ListOptions:   github.ListOptions{PerPage: 50},
+	}
+
+	if since := req.GetString("since", ""); since != "" {
+		t, err := parseSince(since)
+		if err != nil {
+			return toolError(err.Error()), nil
+		}
+		opts.Since = t
+	}
+
+	limit := getLimit(req, 30)
+
+	var notifications []*github.Notification
+	for {
+		page, resp, err := s.restClient.Activity.ListNotifications(ctx, opts)
+		if err != nil {
+			return apiError("Failed to list notifications", err)
+		}
+		notifications = append(notifications, page...)
+
+		if len(notifications) >= limit || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+
+	if len(notifications) == 0 {
+		return mcp.NewToolResultText("No notifications found."), nil
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %d notifications:\n\n", len(notifications)))
+	for _, notification := range notifications {
+		subject := notification.GetSubject()
+		unread := ""
+		if notification.GetUnread() {
+			unread = " (unread)"
+		}
+
+		responseBuilder.WriteString(fmt.Sprintf("- [%s] %s: %s%s\n  Reason: %s, thread ID: %s\n  %s\n",
+			subject.GetType(),
+			notification.GetRepository().GetFullName(),
+			subject.GetTitle(),
+			unread,
+			notification.GetReason(),
+			notification.GetID(),
+			apiToHTMLURL(subject.GetURL(), notification.GetRepository().GetHTMLURL()),
+		))
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func (s *githubService) markNotificationReadHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if !req.GetBool("mark_all", false) {
+		threadID := strings.TrimSpace(req.GetString("thread_id", ""))
+		if threadID == "" {
+			return toolError("Provide a thread_id, or set mark_all to true"), nil
+		}
+
+		if _, err := s.restClient.Activity.MarkThreadRead(ctx, threadID); err != nil {
+			return apiError("Failed to mark notification as read", err)
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Marked 1 notification (thread %s) as read.", threadID)), nil
+	}
+
+	lastRead := time.Now()
+	if value := req.GetString("last_read_at", ""); value != "" {
+		t, err := parseSince(value)
+		if err != nil {
+			return toolError(err.Error()), nil
+		}
+		lastRead = t
+	}
+
+	// The mark-as-read endpoint does not report what it changed, so count the
+	// affected unread notifications first.
+	count := 0
+	opts := &github.NotificationListOptions{
+		Before:      lastRead,
+		ListOptions: github.ListOptions{PerPage: 50},
+	}
+	for {
+		page, resp, err := s.restClient.Activity.ListNotifications(ctx, opts)
+		if err != nil {
+			return apiError("Failed to list notifications", err)
+		}
+		count += len(page)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if count == 0 {
+		return mcp.NewToolResultText("No unread notifications to mark as read."), nil
+	}
+
+	// With many notifications to mark, GitHub answers 202 and marks them
+	// asynchronously.
+	if _, err := s.restClient.Activity.MarkNotificationsRead(ctx, github.Timestamp{Time: lastRead}); err != nil {
+		var accepted *github.AcceptedError
+		if !errors.As(err, &accepted) {
+			return apiError("Failed to mark notifications as read", err)
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Marking %d notifications updated before %s as read; GitHub will finish in the background.", count, formatTimestamp(lastRead, false))), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Marked %d notifications updated before %s as read.", count, formatTimestamp(lastRead, false))), nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMarkAllNotificationsReadAccepted(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /notifications", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `[{"id":"1"},{"id":"2"}]`)
+	})
+	mux.HandleFunc("PUT /notifications", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, `{"message":"Unread notifications couldn't be marked in a single request. Notifications are being marked as read in the background."}`)
+	})
+	s := withREST(t, newTestService(nil), mux)
+
+	result, err := s.markNotificationReadHandler(context.Background(), toolRequest(map[string]interface{}{
+		"mark_all": true,
+	}))
+	if err != nil {
+		t.Fatalf("markNotificationReadHandler: %v", err)
+	}
+	text := resultText(t, result)
+	if result.IsError {
+		t.Fatalf("202 Accepted reported as a failure: %s", text)
+	}
+	if !strings.Contains(text, "Marking 2 notifications") || !strings.Contains(text, "in the background") {
+		t.Errorf("unexpected result: %s", text)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultProfileName names the service built from the unsuffixed
+// credentials (GITHUB_TOKEN, GITHUB_TOKEN_FILE or the GITHUB_APP_* variables).
+const defaultProfileName = "default"
+
+// profileTokenPrefix introduces a named profile: GITHUB_TOKEN_WORK configures
+// the "work" profile.
+const profileTokenPrefix = "GITHUB_TOKEN_"
+
+// serviceProfiles holds one githubService per configured GitHub account, so a
+// single server can act on behalf of several accounts.
+type serviceProfiles struct {
+	primary  string
+	services map[string]*githubService
+}
+
+// profileHandler is a tool handler expressed as a method expression on
+// githubService, such as (*githubService).getIssueHandler, so that the
+// service can be chosen per call.
+type profileHandler func(s *githubService, ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error)
+
+// newServiceProfiles builds and validates a service for the default
+// credentials, if any are set, and for every GITHUB_TOKEN_<NAME> variable.
+// The primary profile is GITHUB_DEFAULT_PROFILE when set, otherwise the
+// default credentials, otherwise the first named profile alphabetically.
+func newServiceProfiles() (*serviceProfiles, error) {
+	ctx := context.Background()
+	profiles := &serviceProfiles{services: map[string]*githubService{}}
+
+	if hasDefaultCredentials() {
+		authorizedClient, isApp, err := newAuthorizedClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		service, err := newGithubService(ctx, authorizedClient, isApp)
+		if err != nil {
+			return nil, err
+		}
+		profiles.services[defaultProfileName] = service
+	}
+
+	for name, token := range namedProfileTokens() {
+		service, err := newGithubService(ctx, newTokenClient(ctx, token), false)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %v", name, err)
+		}
+		profiles.services[name] = service
+		slog.Info("GitHub profile initialized", "profile", name)
+	}
+
+	if len(profiles.services) == 0 {
+		return nil, fmt.Errorf("no GitHub credentials configured: set GITHUB_TOKEN, GITHUB_TOKEN_FILE, GITHUB_APP_ID or a GITHUB_TOKEN_<PROFILE> variable")
+	}
+
+	names := profiles.names()
+	switch primary := strings.ToLower(os.Getenv("GITHUB_DEFAULT_PROFILE")); {
+	case primary != "":
+		if _, ok := profiles.services[primary]; !ok {
+			return nil, fmt.Errorf("GITHUB_DEFAULT_PROFILE %q is not configured. Configured profiles: %s", primary, strings.Join(names, ", "))
+		}
+		profiles.primary = primary
+	case profiles.services[defaultProfileName] != nil:
+		profiles.primary = defaultProfileName
+	default:
+		profiles.primary = names[0]
+	}
+
+	return profiles, nil
+}
+
+func hasDefaultCredentials() bool {
+	return os.Getenv("GITHUB_TOKEN") != "" || os.Getenv("GITHUB_TOKEN_FILE") != "" || os.Getenv("GITHUB_APP_ID") != ""
+}
+
+// namedProfileTokens returns the tokens of GITHUB_TOKEN_<NAME> variables,
+// keyed by lowercased profile name. GITHUB_TOKEN_FILE is not a profile.
+func namedProfileTokens() map[string]string {
+	tokens := map[string]string{}
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(key, profileTokenPrefix) || key == "GITHUB_TOKEN_FILE" {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimPrefix(key, profileTokenPrefix))
+		if name == "" || strings.TrimSpace(value) == "" {
+			continue
+		}
+		tokens[name] = strings.TrimSpace(value)
+	}
+	return tokens
+}
+
+func (p *serviceProfiles) names() []string {
+	names := make([]string, 0, len(p.services))
+	for name := range p.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// handler adapts h into a tool handler that runs against the service named
+// by the call's "profile" argument, or the primary service when it is unset.
+func (p *serviceProfiles) handler(h profileHandler) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name := strings.ToLower(strings.TrimSpace(req.GetString("profile", "")))
+		if name == "" {
+			name = p.primary
+		}
+
+		service, ok := p.services[name]
+		if !ok {
+			return toolError(fmt.Sprintf("Unknown profile %q. Configured profiles: %s", name, strings.Join(p.names(), ", "))), nil
+		}
+
+		return h(service, ctx, req)
+	}
+}
+
+// primaryService returns the service used when no profile is selected.
+func (p *serviceProfiles) primaryService() *githubService {
+	return p.services[p.primary]
+}
+
+// addTool registers tool with an extra optional "profile" argument and routes
+// its calls through handler.
+func (p *serviceProfiles) addTool(s *server.MCPServer, tool mcp.Tool, h profileHandler) {
+	mcp.WithString(
+		"profile",
+		mcp.Description(fmt.Sprintf("Optional GitHub account profile to act as. Configured profiles: %s. Defaults to %s.", strings.Join(p.names(), ", "), p.primary)),
+	)(&tool)
+
+	s.AddTool(tool, p.handler(h))
+}
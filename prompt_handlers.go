@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxPromptDiffBytes caps the diff embedded in a prompt so that large pull
+// requests still leave room in the model's context for the review itself.
+const maxPromptDiffBytes = 100 * 1024
+
+// summarizePRPromptHandler builds a review-summary prompt pre-filled with a
+// pull request's description, diff and unresolved review threads.
+func (s *githubService) summarizePRPromptHandler(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	prURL := strings.TrimSpace(req.Params.Arguments["pull_request_url"])
+	if prURL == "" {
+		return nil, fmt.Errorf("missing required argument: pull_request_url")
+	}
+
+	owner, repo, prNumber, err := parsePRURL(prURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PR URL: %v", err)
+	}
+
+	pr, _, err := s.restClient.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %s", redactSecrets(githubErrorMessage(err)))
+	}
+
+	diff, _, err := s.restClient.PullRequests.GetRaw(ctx, owner, repo, prNumber, github.RawOptions{Type: github.Diff})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch diff: %s", redactSecrets(githubErrorMessage(err)))
+	}
+
+	threads, stats, err := s.getReviewThreads(ctx, owner, repo, prNumber, false)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub GraphQL query failed: %s", redactSecrets(err.Error()))
+	}
+
+	var promptBuilder strings.Builder
+	promptBuilder.WriteString(fmt.Sprintf("Please summarize pull request #%d in %s/%s for a reviewer. ", prNumber, owner, repo))
+	promptBuilder.WriteString("Explain what the change does and why, call out risky or unclear parts of the diff, and list the open review feedback that still needs to be addressed.\n\n")
+
+	promptBuilder.WriteString(fmt.Sprintf("=== PR #%d: %s ===\n", pr.GetNumber(), pr.GetTitle()))
+	promptBuilder.WriteString(fmt.Sprintf("Author: @%s\n", pr.GetUser().GetLogin()))
+	promptBuilder.WriteString(fmt.Sprintf("Branches: %s -> %s\n\n", pr.GetHead().GetLabel(), pr.GetBase().GetRef()))
+	if body := pr.GetBody(); body != "" {
+		promptBuilder.WriteString(fmt.Sprintf("%s\n\n", body))
+	} else {
+		promptBuilder.WriteString("(no description)\n\n")
+	}
+
+	promptBuilder.WriteString("=== Diff ===\n")
+	switch {
+	case diff == "":
+		promptBuilder.WriteString("(no changes)\n")
+	case len(diff) > maxPromptDiffBytes:
+		truncated := truncateAtLine(diff, maxPromptDiffBytes)
+		promptBuilder.WriteString(fmt.Sprintf("```diff\n%s```\n… diff truncated: showing %d of %d bytes\n", truncated, len(truncated), len(diff)))
+	default:
+		promptBuilder.WriteString(fmt.Sprintf("```diff\n%s```\n", diff))
+	}
+
+	unresolved := 0
+	var threadBuilder strings.Builder
+	for _, thread := range threads {
+		if thread.IsResolved || len(thread.Comments.Nodes) == 0 {
+			continue
+		}
+		unresolved++
+
+		threadBuilder.WriteString(fmt.Sprintf("\nThread on %s (%s):\n", string(thread.Comments.Nodes[0].Path), threadLocation(thread)))
+		for _, comment := range thread.Comments.Nodes {
+			threadBuilder.WriteString(fmt.Sprintf("  - %s: %s\n", commentAuthorLine(comment, false), string(comment.Body)))
+		}
+	}
+
+	promptBuilder.WriteString(fmt.Sprintf("\n=== %d unresolved review threads ===\n", unresolved))
+	if unresolved == 0 {
+		promptBuilder.WriteString("(none)\n")
+	} else {
+		promptBuilder.WriteString(threadBuilder.String())
+	}
+	promptBuilder.WriteString(stats.truncationWarning())
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Review summary for %s/%s#%d", owner, repo, prNumber),
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(promptBuilder.String())),
+		},
+	), nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// redactedPlaceholder replaces secrets in text shown to clients and in logs.
+const redactedPlaceholder = "[REDACTED]"
+
+// tokenPatternRegex matches the shapes of GitHub tokens: classic and
+// fine-grained personal access tokens, and OAuth, user-to-server,
+// server-to-server and refresh tokens.
+var tokenPatternRegex = regexp.MustCompile(`github_pat_[A-Za-z0-9_]+|gh[pousr]_[A-Za-z0-9]+`)
+
+var secrets = struct {
+	sync.Mutex
+	values []string
+}{}
+
+// registerSecret records a credential value that must never appear in output,
+// such as a token read from a file.
+func registerSecret(value string) {
+	if value = strings.TrimSpace(value); value == "" {
+		return
+	}
+
+	secrets.Lock()
+	defer secrets.Unlock()
+	secrets.values = append(secrets.values, value)
+}
+
+// redactSecrets masks the configured token values and anything shaped like a
+// GitHub token in text.
+func redactSecrets(text string) string {
+	known := []string{os.Getenv("GITHUB_TOKEN")}
+	for _, token := range namedProfileTokens() {
+		known = append(known, token)
+	}
+
+	secrets.Lock()
+	known = append(known, secrets.values...)
+	secrets.Unlock()
+
+	for _, secret := range known {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, redactedPlaceholder)
+		}
+	}
+
+	return tokenPatternRegex.ReplaceAllString(text, redactedPlaceholder)
+}
+
+// toolError is mcp.NewToolResultError with secrets redacted from message.
+// Handlers use it for every error result they return, since messages often
+// embed errors and URLs from the GitHub client.
+func toolError(message string) *mcp.CallToolResult {
+	return mcp.NewToolResultError(redactSecrets(message))
+}
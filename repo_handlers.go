@@ -0,0 +1,750 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/shurcooL/githubv4"
+)
+
+// maxFileContentBytes is the largest file get_file_contents returns inline.
+const maxFileContentBytes = 512 * 1024
+
+func (s *githubService) getFileContentsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	path, err := req.RequireString("path")
+	if err != nil {
+		return toolError("Missing required argument: path"), nil
+	}
+	path = strings.Trim(path, "/")
+
+	ref := req.GetString("ref", "")
+	refText := ref
+	if refText == "" {
+		refText = "the default branch"
+	}
+
+	file, dir, resp, err := s.restClient.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return toolError(fmt.Sprintf("File not found: %s at %s in %s/%s", path, refText, owner, repo)), nil
+		}
+		return apiError("Failed to get contents", err)
+	}
+
+	if dir != nil {
+		var responseBuilder strings.Builder
+		responseBuilder.WriteString(fmt.Sprintf("%s is a directory with %d entries (at %s):\n\n", displayPath(path), len(dir), refText))
+		for _, entry := range dir {
+			name := entry.GetName()
+			if entry.GetType() == "dir" {
+				name += "/"
+			}
+			responseBuilder.WriteString(fmt.Sprintf("- %s\n", name))
+		}
+		return mcp.NewToolResultText(responseBuilder.String()), nil
+	}
+
+	if file.GetSize() > maxFileContentBytes {
+		return toolError(fmt.Sprintf("%s is %d bytes, larger than the %d byte limit. View it at %s", path, file.GetSize(), maxFileContentBytes, file.GetHTMLURL())), nil
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return toolError(fmt.Sprintf("Failed to decode %s: %v", path, err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("%s (at %s, %d bytes):\n\n%s", path, refText, file.GetSize(), content)), nil
+}
+
+func (s *githubService) createOrUpdateFileHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	path := strings.Trim(req.GetString("path", ""), "/")
+	if path == "" {
+		return toolError("Missing required argument: path"), nil
+	}
+
+	content, err := req.RequireString("content")
+	if err != nil {
+		return toolError("Missing required argument: content"), nil
+	}
+
+	message := strings.TrimSpace(req.GetString("message", ""))
+	if message == "" {
+		return toolError("Missing required argument: message"), nil
+	}
+
+	repository, _, err := s.restClient.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return apiError("Failed to get repository", err)
+	}
+
+	// Committing straight to the default branch has to be asked for
+	// explicitly, so a forgotten branch argument cannot land on main.
+	branch := strings.TrimSpace(req.GetString("branch", ""))
+	if branch == "" {
+		branch = repository.GetDefaultBranch()
+	}
+	if branch == repository.GetDefaultBranch() && !req.GetBool("allow_default", false) {
+		return toolError(fmt.Sprintf("Refusing to commit directly to the default branch %s of %s/%s. Pass a different branch, or set allow_default to true.", branch, owner, repo)), nil
+	}
+
+	opts := &github.RepositoryContentFileOptions{
+		Message: github.String(message),
+		Content: []byte(content),
+		Branch:  github.String(branch),
+	}
+
+	existing, dir, resp, err := s.restClient.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: branch})
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return apiError("Failed to look up existing file", err)
+	}
+	if dir != nil {
+		return toolError(fmt.Sprintf("%s is a directory in %s/%s", path, owner, repo)), nil
+	}
+
+	var result *github.RepositoryContentResponse
+	action := "Created"
+	if existing != nil {
+		action = "Updated"
+		opts.SHA = existing.SHA
+		result, _, err = s.restClient.Repositories.UpdateFile(ctx, owner, repo, path, opts)
+		if err != nil {
+			return apiError(fmt.Sprintf("Failed to update %s", path), err)
+		}
+	} else {
+		result, _, err = s.restClient.Repositories.CreateFile(ctx, owner, repo, path, opts)
+		if err != nil {
+			return apiError(fmt.Sprintf("Failed to create %s", path), err)
+		}
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("%s %s on %s in commit %s.\n%s",
+		action,
+		path,
+		branch,
+		shortSHA(result.Commit.GetSHA()),
+		result.Content.GetHTMLURL(),
+	)), nil
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "The repository root"
+	}
+	return path
+}
+
+func (s *githubService) listBranchesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	prefix := req.GetString("prefix", "")
+
+	var branches []*github.Branch
+	opts := &github.BranchListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := s.restClient.Repositories.ListBranches(ctx, owner, repo, opts)
+		if err != nil {
+			return apiError("Failed to list branches", err)
+		}
+
+		for _, branch := range page {
+			if strings.HasPrefix(branch.GetName(), prefix) {
+				branches = append(branches, branch)
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(branches) == 0 {
+		if prefix != "" {
+			return mcp.NewToolResultText(fmt.Sprintf("No branches starting with %q found in %s/%s.", prefix, owner, repo)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("No branches found in %s/%s.", owner, repo)), nil
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %d branches in %s/%s:\n\n", len(branches), owner, repo))
+	for _, branch := range branches {
+		protected := ""
+		if branch.GetProtected() {
+			protected = " (protected)"
+		}
+		responseBuilder.WriteString(fmt.Sprintf("- %s%s\n", branch.GetName(), protected))
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+// resolveRefSHA returns the commit SHA a branch, tag, or commit SHA points
+// to. Branches are looked up first so a branch shadows a tag of the same name.
+func (s *githubService) resolveRefSHA(ctx context.Context, owner, repo, ref string) (string, *github.Response, error) {
+	branchRef, resp, err := s.restClient.Git.GetRef(ctx, owner, repo, "heads/"+ref)
+	if err == nil {
+		return branchRef.GetObject().GetSHA(), resp, nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return "", resp, err
+	}
+
+	return s.restClient.Repositories.GetCommitSHA1(ctx, owner, repo, ref, "")
+}
+
+func (s *githubService) createBranchHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	branch := strings.TrimPrefix(strings.TrimSpace(req.GetString("branch", "")), "refs/heads/")
+	if branch == "" {
+		return toolError("Missing required argument: branch"), nil
+	}
+
+	fromRef := strings.TrimSpace(req.GetString("from_ref", ""))
+	if fromRef == "" {
+		return toolError("Missing required argument: from_ref"), nil
+	}
+
+	repository, _, err := s.restClient.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return apiError("Failed to get repository", err)
+	}
+
+	if _, resp, err := s.restClient.Git.GetRef(ctx, owner, repo, "heads/"+branch); err == nil {
+		return toolError(fmt.Sprintf("Branch %q already exists in %s/%s", branch, owner, repo)), nil
+	} else if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return apiError("Failed to check for an existing branch", err)
+	}
+
+	sha, resp, err := s.resolveRefSHA(ctx, owner, repo, fromRef)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity) {
+			return toolError(fmt.Sprintf("Ref %s not found in %s/%s", fromRef, owner, repo)), nil
+		}
+		return apiError("Failed to resolve from_ref", err)
+	}
+
+	_, _, err = s.restClient.Git.CreateRef(ctx, owner, repo, &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: github.String(sha)},
+	})
+	if err != nil {
+		return apiError("Failed to create branch", err)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Created branch %s from %s (%s).\n%s/tree/%s", branch, fromRef, shortSHA(sha), repository.GetHTMLURL(), branch)), nil
+}
+
+// getBlameHandler reports the commit that last changed one line of a file,
+// using GraphQL blame since the REST API has no blame endpoint.
+func (s *githubService) getBlameHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	path := strings.Trim(req.GetString("path", ""), "/")
+	if path == "" {
+		return toolError("Missing required argument: path"), nil
+	}
+
+	line := req.GetInt("line", 0)
+	if line < 1 {
+		return toolError("line must be a positive line number"), nil
+	}
+
+	ref := strings.TrimSpace(req.GetString("ref", ""))
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	var query blameQuery
+	variables := map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"repo":  githubv4.String(repo),
+		"ref":   githubv4.String(ref),
+		"path":  githubv4.String(path),
+	}
+	if err := s.graphqlClient.Query(ctx, &query, variables); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "could not resolve file") {
+			return toolError(fmt.Sprintf("File not found: %s at %s in %s/%s", path, ref, owner, repo)), nil
+		}
+		return apiError("GitHub GraphQL query failed", err)
+	}
+
+	commit := query.Repository.Object.Commit
+	if commit.Oid == "" {
+		commit = query.Repository.Object.Tag.Target.Commit
+	}
+	if commit.Oid == "" {
+		return toolError(fmt.Sprintf("Ref %s not found in %s/%s", ref, owner, repo)), nil
+	}
+
+	ranges := commit.Blame.Ranges
+	lineCount := 0
+	if len(ranges) > 0 {
+		lineCount = int(ranges[len(ranges)-1].EndingLine)
+	}
+
+	for _, blame := range ranges {
+		if line < int(blame.StartingLine) || line > int(blame.EndingLine) {
+			continue
+		}
+
+		author := string(blame.Commit.Author.Name)
+		if login := string(blame.Commit.Author.User.Login); login != "" {
+			author = fmt.Sprintf("%s (@%s)", author, login)
+		}
+
+		var responseBuilder strings.Builder
+		responseBuilder.WriteString(fmt.Sprintf("Line %d of %s (at %s) was last changed in %s by %s on %s:\n",
+			line,
+			path,
+			ref,
+			shortSHA(string(blame.Commit.Oid)),
+			author,
+			formatTimestamp(blame.Commit.CommittedDate.Time, false),
+		))
+		responseBuilder.WriteString(fmt.Sprintf("  %s\n", string(blame.Commit.MessageHeadline)))
+		if blame.StartingLine != blame.EndingLine {
+			responseBuilder.WriteString(fmt.Sprintf("  Lines %d-%d come from the same commit.\n", int(blame.StartingLine), int(blame.EndingLine)))
+		}
+		responseBuilder.WriteString(fmt.Sprintf("  %s\n", blame.Commit.URL.String()))
+
+		return mcp.NewToolResultText(responseBuilder.String()), nil
+	}
+
+	return toolError(fmt.Sprintf("Line %d is outside %s at %s, which has %d lines", line, path, ref, lineCount)), nil
+}
+
+// maxCommitFiles is the most files GitHub returns for a single commit.
+const maxCommitFiles = 3000
+
+func (s *githubService) getCommitHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	sha := strings.TrimSpace(req.GetString("sha", ""))
+	if sha == "" {
+		return toolError("Missing required argument: sha"), nil
+	}
+
+	includePatch := req.GetBool("include_patch", false)
+
+	// GitHub resolves abbreviated SHAs itself, so they are passed through as-is.
+	opts := &github.ListOptions{PerPage: 100}
+	commit, resp, err := s.restClient.Repositories.GetCommit(ctx, owner, repo, sha, opts)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity) {
+			return toolError(fmt.Sprintf("Commit %s not found in %s/%s", sha, owner, repo)), nil
+		}
+		return apiError("Failed to get commit", err)
+	}
+
+	// The commit's files are paginated; later pages repeat the commit itself.
+	for resp.NextPage != 0 {
+		opts.Page = resp.NextPage
+		var page *github.RepositoryCommit
+		page, resp, err = s.restClient.Repositories.GetCommit(ctx, owner, repo, commit.GetSHA(), opts)
+		if err != nil {
+			return apiError("Failed to get commit files", err)
+		}
+		commit.Files = append(commit.Files, page.Files...)
+	}
+
+	author := commit.GetAuthor().GetLogin()
+	if author == "" {
+		author = commit.GetCommit().GetAuthor().GetName()
+	} else {
+		author = "@" + author
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Commit %s\n", commit.GetSHA()))
+	responseBuilder.WriteString(fmt.Sprintf("Author: %s (%s)\n", author, formatTimestamp(commit.GetCommit().GetAuthor().GetDate().Time, false)))
+	responseBuilder.WriteString(fmt.Sprintf("URL: %s\n\n", commit.GetHTMLURL()))
+	responseBuilder.WriteString(fmt.Sprintf("%s\n\n", commit.GetCommit().GetMessage()))
+
+	stats := commit.GetStats()
+	responseBuilder.WriteString(fmt.Sprintf("%d files changed (+%d -%d):\n", len(commit.Files), stats.GetAdditions(), stats.GetDeletions()))
+	for _, file := range commit.Files {
+		responseBuilder.WriteString(fmt.Sprintf("- [%s] %s (+%d -%d)\n",
+			file.GetStatus(),
+			file.GetFilename(),
+			file.GetAdditions(),
+			file.GetDeletions(),
+		))
+
+		if includePatch && file.GetPatch() != "" {
+			responseBuilder.WriteString(fmt.Sprintf("```diff\n%s\n```\n", file.GetPatch()))
+		}
+	}
+
+	if len(commit.Files) >= maxCommitFiles {
+		responseBuilder.WriteString(fmt.Sprintf("\nGitHub lists at most %d files per commit, so the list may be incomplete; the line counts cover every file.\n", maxCommitFiles))
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func (s *githubService) compareCommitsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	base := strings.TrimSpace(req.GetString("base", ""))
+	head := strings.TrimSpace(req.GetString("head", ""))
+	if base == "" || head == "" {
+		return toolError("Both base and head are required"), nil
+	}
+
+	includePatch := req.GetBool("include_patch", false)
+
+	comparison, resp, err := s.restClient.Repositories.CompareCommits(ctx, owner, repo, base, head, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return toolError(fmt.Sprintf("Could not compare %s...%s in %s/%s: one of the refs does not exist", base, head, owner, repo)), nil
+		}
+		return apiError("Failed to compare commits", err)
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Comparing %s...%s in %s/%s: %s\n", base, head, owner, repo, comparison.GetStatus()))
+	responseBuilder.WriteString(fmt.Sprintf("%s is %d commits ahead of and %d commits behind %s\n", head, comparison.GetAheadBy(), comparison.GetBehindBy(), base))
+	responseBuilder.WriteString(fmt.Sprintf("URL: %s\n", comparison.GetHTMLURL()))
+
+	responseBuilder.WriteString(fmt.Sprintf("\n%d commits:\n", comparison.GetTotalCommits()))
+	for _, commit := range comparison.Commits {
+		author := commit.GetAuthor().GetLogin()
+		if author == "" {
+			author = commit.GetCommit().GetAuthor().GetName()
+		} else {
+			author = "@" + author
+		}
+		subject, _, _ := strings.Cut(commit.GetCommit().GetMessage(), "\n")
+		responseBuilder.WriteString(fmt.Sprintf("- %s %s (%s)\n", shortSHA(commit.GetSHA()), subject, author))
+	}
+	responseBuilder.WriteString(paginationFooter(len(comparison.Commits), comparison.GetTotalCommits()))
+
+	additions, deletions := 0, 0
+	for _, file := range comparison.Files {
+		additions += file.GetAdditions()
+		deletions += file.GetDeletions()
+	}
+	responseBuilder.WriteString(fmt.Sprintf("\n%d files changed (+%d -%d):\n", len(comparison.Files), additions, deletions))
+	for _, file := range comparison.Files {
+		responseBuilder.WriteString(fmt.Sprintf("- [%s] %s (+%d -%d)\n",
+			file.GetStatus(),
+			file.GetFilename(),
+			file.GetAdditions(),
+			file.GetDeletions(),
+		))
+
+		if includePatch && file.GetPatch() != "" {
+			responseBuilder.WriteString(fmt.Sprintf("```diff\n%s\n```\n", file.GetPatch()))
+		}
+	}
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+// maxReleaseNotesChars is how much of a release's notes is shown unless the
+// caller asks for the full text.
+const maxReleaseNotesChars = 500
+
+func writeRelease(b *strings.Builder, release *github.RepositoryRelease, fullNotes bool) {
+	name := release.GetName()
+	if name == "" {
+		name = release.GetTagName()
+	}
+
+	flags := ""
+	if release.GetPrerelease() {
+		flags += " [prerelease]"
+	}
+	if release.GetDraft() {
+		flags += " [draft]"
+	}
+
+	published := "unpublished"
+	if !release.GetPublishedAt().IsZero() {
+		published = formatTimestamp(release.GetPublishedAt().Time, false)
+	}
+
+	b.WriteString(fmt.Sprintf("=== %s (%s)%s ===\n", name, release.GetTagName(), flags))
+	b.WriteString(fmt.Sprintf("Published: %s\n", published))
+	b.WriteString(fmt.Sprintf("URL: %s\n", release.GetHTMLURL()))
+
+	notes := strings.TrimSpace(release.GetBody())
+	if notes == "" {
+		b.WriteString("\n(no release notes)\n\n")
+		return
+	}
+	if !fullNotes && len(notes) > maxReleaseNotesChars {
+		end := maxReleaseNotesChars
+		for end > 0 && !utf8.RuneStart(notes[end]) {
+			end--
+		}
+		notes = notes[:end] + "\n… (truncated, set full_notes to see everything)"
+	}
+	b.WriteString(fmt.Sprintf("\n%s\n\n", notes))
+}
+
+func (s *githubService) listReleasesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	limit := getLimit(req, 10)
+	fullNotes := req.GetBool("full_notes", false)
+
+	var releases []*github.RepositoryRelease
+	opts := &github.ListOptions{PerPage: min(limit, 100)}
+	var resp *github.Response
+	for len(releases) < limit {
+		var page []*github.RepositoryRelease
+		page, resp, err = s.restClient.Repositories.ListReleases(ctx, owner, repo, opts)
+		if err != nil {
+			return apiError("Failed to list releases", err)
+		}
+		releases = append(releases, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(releases) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No releases found in %s/%s.", owner, repo)), nil
+	}
+
+	// The releases API has no total count. Every page but the last is full,
+	// so fetching the last page gives the exact total in one more request.
+	total := len(releases)
+	if resp.NextPage != 0 {
+		total = unknownTotal
+		if resp.LastPage != 0 {
+			opts.Page = resp.LastPage
+			lastPage, _, err := s.restClient.Repositories.ListReleases(ctx, owner, repo, opts)
+			if err != nil {
+				return apiError("Failed to count releases", err)
+			}
+			total = (resp.LastPage-1)*opts.PerPage + len(lastPage)
+		}
+	}
+
+	shown := releases[:min(len(releases), limit)]
+	found := fmt.Sprint(total)
+	if total == unknownTotal {
+		found = fmt.Sprintf("more than %d", len(shown))
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %s releases in %s/%s, showing %d:\n\n", found, owner, repo, len(shown)))
+	for _, release := range shown {
+		writeRelease(&responseBuilder, release, fullNotes)
+	}
+
+	responseBuilder.WriteString(paginationFooter(len(shown), total))
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func (s *githubService) getLatestReleaseHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repoArg, err := req.RequireString("repo")
+	if err != nil {
+		return toolError("Missing required argument: repo"), nil
+	}
+
+	owner, repo, err := parseRepo(repoArg)
+	if err != nil {
+		return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+	}
+
+	release, resp, err := s.restClient.Repositories.GetLatestRelease(ctx, owner, repo)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return mcp.NewToolResultText(fmt.Sprintf("No published releases found in %s/%s.", owner, repo)), nil
+		}
+		return apiError("Failed to get latest release", err)
+	}
+
+	var responseBuilder strings.Builder
+	writeRelease(&responseBuilder, release, req.GetBool("full_notes", false))
+
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+// repoVisibility returns "public", "private", or "internal" for a repository.
+func repoVisibility(repository *github.Repository) string {
+	if visibility := repository.GetVisibility(); visibility != "" {
+		return visibility
+	}
+	if repository.GetPrivate() {
+		return "private"
+	}
+	return "public"
+}
+
+func (s *githubService) listRepositoriesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	org := strings.TrimSpace(req.GetString("org", ""))
+	nameFilter := strings.ToLower(req.GetString("name_contains", ""))
+	limit := getLimit(req, 30)
+
+	sort := req.GetString("sort", "updated")
+	if sort != "updated" && sort != "pushed" {
+		return toolError(fmt.Sprintf("Invalid sort %q: must be updated or pushed", sort)), nil
+	}
+
+	listPage := func(page int) ([]*github.Repository, *github.Response, error) {
+		listOpts := github.ListOptions{PerPage: 100, Page: page}
+		if org != "" {
+			return s.restClient.Repositories.ListByOrg(ctx, org, &github.RepositoryListByOrgOptions{
+				Sort:        sort,
+				Direction:   "desc",
+				ListOptions: listOpts,
+			})
+		}
+		return s.restClient.Repositories.ListByAuthenticatedUser(ctx, &github.RepositoryListByAuthenticatedUserOptions{
+			Sort:        sort,
+			Direction:   "desc",
+			ListOptions: listOpts,
+		})
+	}
+
+	// The name filter is applied client-side, so keep paging until enough
+	// repositories match or the listing runs out.
+	var repos []*github.Repository
+	more := false
+	page := 1
+	for {
+		result, resp, err := listPage(page)
+		if err != nil {
+			return apiError("Failed to list repositories", err)
+		}
+
+		for _, repository := range result {
+			if strings.Contains(strings.ToLower(repository.GetName()), nameFilter) {
+				repos = append(repos, repository)
+			}
+		}
+
+		if len(repos) > limit || (len(repos) == limit && resp.NextPage != 0) {
+			more = true
+			break
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	if len(repos) > limit {
+		repos = repos[:limit]
+	}
+
+	owner := "you"
+	if org != "" {
+		owner = org
+	}
+
+	if len(repos) == 0 {
+		if nameFilter != "" {
+			return mcp.NewToolResultText(fmt.Sprintf("No repositories with names containing %q found for %s.", nameFilter, owner)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("No repositories found for %s.", owner)), nil
+	}
+
+	// Neither listing endpoint reports a total, and the name filter runs
+	// client-side, so only the count collected so far is known.
+	total := len(repos)
+	found := fmt.Sprint(total)
+	if more {
+		total = unknownTotal
+		found = fmt.Sprintf("more than %d", len(repos))
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %s repositories for %s, showing %d (most recently %s first):\n\n", found, owner, len(repos), sort))
+	for _, repository := range repos {
+		archived := ""
+		if repository.GetArchived() {
+			archived = ", archived"
+		}
+		responseBuilder.WriteString(fmt.Sprintf("- %s [%s%s] default branch: %s\n  %s\n",
+			repository.GetFullName(),
+			repoVisibility(repository),
+			archived,
+			repository.GetDefaultBranch(),
+			repository.GetHTMLURL(),
+		))
+	}
+
+	responseBuilder.WriteString(paginationFooter(len(repos), total))
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
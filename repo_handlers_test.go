@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/shurcooL/githubv4"
+)
+
+const blameRangesJSON = `"blame": {"ranges": [{"startingLine": 1, "endingLine": 3, "commit": {
+	"oid": "abcdef1234567890", "messageHeadline": "Add the thing", "committedDate": "2024-05-01T12:00:00Z",
+	"url": "https://github.com/owner/repo/commit/abcdef1234567890", "author": {"name": "Mona", "user": {"login": "octocat"}}
+}}]}`
+
+// blameServer answers blame queries with object, the JSON the ref resolves
+// to, and records the queries it received.
+func blameServer(t *testing.T, object string, requests *[]graphqlRequest) graphqlQuerier {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body graphqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding GraphQL request: %v", err)
+		}
+		*requests = append(*requests, body)
+		fmt.Fprintf(w, `{"data": {"repository": {"object": %s}}}`, object)
+	}))
+	t.Cleanup(server.Close)
+	return githubv4.NewEnterpriseClient(server.URL, server.Client())
+}
+
+func TestGetBlameHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		ref    string
+		object string
+	}{
+		{"commit", "main", `{"oid": "1111111111111111", ` + blameRangesJSON + `}`},
+		{"annotated tag", "v1.0.0", `{"target": {"oid": "1111111111111111", ` + blameRangesJSON + `}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []graphqlRequest
+			s := newTestService(blameServer(t, tt.object, &requests))
+
+			result, err := s.getBlameHandler(context.Background(), toolRequest(map[string]interface{}{
+				"repo": "owner/repo",
+				"path": "main.go",
+				"line": float64(2),
+				"ref":  tt.ref,
+			}))
+			if err != nil {
+				t.Fatalf("getBlameHandler: %v", err)
+			}
+			if result.IsError {
+				t.Fatalf("getBlameHandler returned a tool error: %s", resultText(t, result))
+			}
+
+			if len(requests) != 1 || !strings.Contains(requests[0].Query, "... on Tag{target{... on Commit{") {
+				t.Errorf("query does not peel annotated tags:\n%v", requests)
+			}
+			text := resultText(t, result)
+			want := fmt.Sprintf("Line 2 of main.go (at %s) was last changed in abcdef1 by Mona (@octocat)", tt.ref)
+			if !strings.Contains(text, want) {
+				t.Errorf("result missing %q:\n%s", want, text)
+			}
+		})
+	}
+}
+
+func TestGetBlameHandlerUnknownRef(t *testing.T) {
+	var requests []graphqlRequest
+	s := newTestService(blameServer(t, "null", &requests))
+
+	result, err := s.getBlameHandler(context.Background(), toolRequest(map[string]interface{}{
+		"repo": "owner/repo",
+		"path": "main.go",
+		"line": float64(2),
+		"ref":  "nope",
+	}))
+	if err != nil {
+		t.Fatalf("getBlameHandler: %v", err)
+	}
+	if !result.IsError || !strings.Contains(resultText(t, result), "Ref nope not found") {
+		t.Errorf("want a ref-not-found tool error, got:\n%s", resultText(t, result))
+	}
+}
+
+func TestGetCommitHandlerPagesFiles(t *testing.T) {
+	const fileCount = 250
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/commits/abc123", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+
+		var files []string
+		for i := (page - 1) * perPage; i < min(page*perPage, fileCount); i++ {
+			files = append(files, fmt.Sprintf(`{"filename":"file%d.go","status":"modified","additions":1}`, i))
+		}
+		if page*perPage < fileCount {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v3/repos/owner/repo/commits/abc123?page=%d>; rel="next"`, r.Host, page+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"sha":"abc123","stats":{"additions":%d,"deletions":0},"files":[%s]}`, fileCount, strings.Join(files, ","))
+	})
+	s := withREST(t, newTestService(nil), mux)
+
+	result, err := s.getCommitHandler(context.Background(), toolRequest(map[string]interface{}{
+		"repo": "owner/repo",
+		"sha":  "abc123",
+	}))
+	if err != nil {
+		t.Fatalf("getCommitHandler: %v", err)
+	}
+	text := resultText(t, result)
+
+	if !strings.Contains(text, "250 files changed (+250 -0):") {
+		t.Errorf("unexpected header:\n%s", text)
+	}
+	if got := strings.Count(text, "- [modified] "); got != fileCount {
+		t.Errorf("listed %d files, want %d", got, fileCount)
+	}
+	if strings.Contains(text, "may be incomplete") {
+		t.Errorf("complete file list has a truncation note")
+	}
+}
+
+func TestWriteReleaseTruncatesOnRuneBoundary(t *testing.T) {
+	// Each "€" is three bytes, so maxReleaseNotesChars falls inside one.
+	notes := strings.Repeat("€", maxReleaseNotesChars)
+	release := &github.RepositoryRelease{TagName: github.String("v1.0.0"), Body: github.String(notes)}
+
+	var b strings.Builder
+	writeRelease(&b, release, false)
+
+	if !utf8.ValidString(b.String()) {
+		t.Errorf("truncated notes are not valid UTF-8:\n%q", b.String())
+	}
+	if !strings.Contains(b.String(), "(truncated, set full_notes to see everything)") {
+		t.Errorf("notes were not truncated:\n%s", b.String())
+	}
+}
+
+func TestListRepositoriesHandlerFooter(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/acme/repos", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v3/orgs/acme/repos?page=2>; rel="next"`, r.Host))
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `[{"name":"one","full_name":"acme/one"},{"name":"two","full_name":"acme/two"},{"name":"three","full_name":"acme/three"}]`)
+	})
+	s := withREST(t, newTestService(nil), mux)
+
+	result, err := s.listRepositoriesHandler(context.Background(), toolRequest(map[string]interface{}{
+		"org":   "acme",
+		"limit": float64(2),
+	}))
+	if err != nil {
+		t.Fatalf("listRepositoriesHandler: %v", err)
+	}
+	text := resultText(t, result)
+
+	for _, want := range []string{
+		"Found more than 2 repositories for acme, showing 2 (most recently updated first):",
+		paginationFooter(2, unknownTotal),
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("result missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "acme/three") {
+		t.Errorf("result exceeds the limit:\n%s", text)
+	}
+}
+
+func TestListReleasesHandlerStopsAtLimit(t *testing.T) {
+	const releaseCount = 23
+	var pages []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		lastPage := (releaseCount + perPage - 1) / perPage
+
+		var releases []string
+		for i := (page - 1) * perPage; i < min(page*perPage, releaseCount); i++ {
+			releases = append(releases, fmt.Sprintf(`{"tag_name":"v0.%d.0"}`, i))
+		}
+		if page < lastPage {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%[1]s/api/v3/repos/owner/repo/releases?page=%[2]d>; rel="next", <http://%[1]s/api/v3/repos/owner/repo/releases?page=%[3]d>; rel="last"`, r.Host, page+1, lastPage))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, "[%s]", strings.Join(releases, ","))
+	})
+	s := withREST(t, newTestService(nil), mux)
+
+	result, err := s.listReleasesHandler(context.Background(), toolRequest(map[string]interface{}{
+		"repo":  "owner/repo",
+		"limit": float64(10),
+	}))
+	if err != nil {
+		t.Fatalf("listReleasesHandler: %v", err)
+	}
+	text := resultText(t, result)
+
+	if want := []string{"", "3"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("requested pages %q, want %q", pages, want)
+	}
+	for _, want := range []string{"Found 23 releases in owner/repo, showing 10:", paginationFooter(10, releaseCount)} {
+		if !strings.Contains(text, want) {
+			t.Errorf("result missing %q:\n%s", want, text)
+		}
+	}
+}
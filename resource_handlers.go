@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// prResourceURIRegex matches pull request resource URIs of the form
+// github://pr/owner/repo/123.
+var prResourceURIRegex = regexp.MustCompile(`^github://pr/([^/]+)/([^/]+)/(\d+)$`)
+
+func parsePRResourceURI(uri string) (owner string, repo string, number int, err error) {
+	matches := prResourceURIRegex.FindStringSubmatch(strings.TrimSpace(uri))
+	if len(matches) != 4 {
+		return "", "", 0, fmt.Errorf("invalid pull request resource URI. Expected: github://pr/owner/repo/123")
+	}
+
+	owner = matches[1]
+	repo = matches[2]
+	number, err = strconv.Atoi(matches[3])
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid PR number: %s", matches[3])
+	}
+
+	return owner, repo, number, nil
+}
+
+// pullRequestResourceHandler renders a pull request's description and
+// unresolved review threads as a readable Markdown resource.
+func (s *githubService) pullRequestResourceHandler(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	owner, repo, prNumber, err := parsePRResourceURI(req.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, _, err := s.restClient.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %s", redactSecrets(githubErrorMessage(err)))
+	}
+
+	threads, stats, err := s.getReviewThreads(ctx, owner, repo, prNumber, false)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub GraphQL query failed: %s", redactSecrets(err.Error()))
+	}
+
+	var contentBuilder strings.Builder
+	contentBuilder.WriteString(fmt.Sprintf("# PR #%d: %s\n\n", pr.GetNumber(), pr.GetTitle()))
+	contentBuilder.WriteString(fmt.Sprintf("- Author: @%s\n", pr.GetUser().GetLogin()))
+	contentBuilder.WriteString(fmt.Sprintf("- State: %s\n", pr.GetState()))
+	contentBuilder.WriteString(fmt.Sprintf("- Branches: %s -> %s\n", pr.GetHead().GetLabel(), pr.GetBase().GetRef()))
+	contentBuilder.WriteString(fmt.Sprintf("- URL: %s\n\n", pr.GetHTMLURL()))
+
+	if body := pr.GetBody(); body != "" {
+		contentBuilder.WriteString(fmt.Sprintf("%s\n\n", body))
+	} else {
+		contentBuilder.WriteString("(no description)\n\n")
+	}
+
+	var threadBuilder strings.Builder
+	unresolved := 0
+	for _, thread := range threads {
+		if thread.IsResolved || len(thread.Comments.Nodes) == 0 {
+			continue
+		}
+		unresolved++
+
+		firstComment := thread.Comments.Nodes[0]
+		threadBuilder.WriteString(fmt.Sprintf("\n### %s (%s)\n\n", string(firstComment.Path), threadLocation(thread)))
+		for _, comment := range thread.Comments.Nodes {
+			threadBuilder.WriteString(fmt.Sprintf("- %s: %s\n", commentAuthorLine(comment, false), string(comment.Body)))
+		}
+		threadBuilder.WriteString(fmt.Sprintf("\n[Thread link](%s)\n", firstComment.URL.String()))
+	}
+
+	contentBuilder.WriteString(fmt.Sprintf("## Unresolved review threads (%d)\n", unresolved))
+	if unresolved == 0 {
+		contentBuilder.WriteString("\nNone.\n")
+	} else {
+		contentBuilder.WriteString(threadBuilder.String())
+	}
+	contentBuilder.WriteString(stats.truncationWarning())
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      req.Params.URI,
+			MIMEType: "text/markdown",
+			Text:     contentBuilder.String(),
+		},
+	}, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// reviewBatch is a draft review assembled across start_review and
+// add_review_comment_to_batch calls, posted as one review by submit_review.
+type reviewBatch struct {
+	// commitID is the head commit when the review was started, so every
+	// comment is anchored to the code that was actually reviewed.
+	commitID string
+	comments []*github.DraftReviewComment
+}
+
+// reviewBatches holds the draft reviews in progress, keyed by pull request.
+// Batches live in memory only and are lost when the server restarts.
+type reviewBatches struct {
+	mu      sync.Mutex
+	batches map[string]*reviewBatch
+}
+
+// reviewBatchKey identifies a pull request regardless of how its owner and
+// repository names were capitalized in the URL.
+func reviewBatchKey(owner, repo string, number int) string {
+	return strings.ToLower(fmt.Sprintf("%s/%s/%d", owner, repo, number))
+}
+
+func newReviewBatches() *reviewBatches {
+	return &reviewBatches{batches: map[string]*reviewBatch{}}
+}
+
+// start begins a batch for key. It returns the existing batch instead,
+// leaving it in place, when one is already in progress and replace is false.
+func (b *reviewBatches) start(key, commitID string, replace bool) (existing *reviewBatch, started bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if batch, ok := b.batches[key]; ok && !replace {
+		return batch, false
+	}
+	b.batches[key] = &reviewBatch{commitID: commitID}
+	return nil, true
+}
+
+// add appends a comment to the batch for key, returning the new comment
+// count, or false when no batch is in progress.
+func (b *reviewBatches) add(key string, comment *github.DraftReviewComment) (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	batch, ok := b.batches[key]
+	if !ok {
+		return 0, false
+	}
+	batch.comments = append(batch.comments, comment)
+	return len(batch.comments), true
+}
+
+// snapshot returns a copy of the batch for key, safe to use without the lock.
+func (b *reviewBatches) snapshot(key string) (reviewBatch, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	batch, ok := b.batches[key]
+	if !ok {
+		return reviewBatch{}, false
+	}
+	return reviewBatch{
+		commitID: batch.commitID,
+		comments: append([]*github.DraftReviewComment(nil), batch.comments...),
+	}, true
+}
+
+// finish removes the batch for key once it has been submitted.
+func (b *reviewBatches) finish(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.batches, key)
+}
@@ -0,0 +1,508 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+)
+
+// orgLoginRegex matches a valid GitHub organization or user login: letters,
+// digits and single hyphens, not starting or ending with a hyphen.
+var orgLoginRegex = regexp.MustCompile(`^[A-Za-z0-9](?:-?[A-Za-z0-9])*$`)
+
+// hasSearchScope reports whether a search query is restricted to a
+// repository, organization, or user, which GitHub's code search requires.
+func hasSearchScope(query string) bool {
+	for _, term := range strings.Fields(query) {
+		if strings.HasPrefix(term, "repo:") || strings.HasPrefix(term, "org:") || strings.HasPrefix(term, "user:") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *githubService) searchCodeHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := req.RequireString("query")
+	if err != nil || strings.TrimSpace(query) == "" {
+		return toolError("Missing required argument: query"), nil
+	}
+
+	queryParts := []string{strings.TrimSpace(query)}
+
+	if repoArg := req.GetString("repo", ""); repoArg != "" {
+		owner, repo, err := parseRepo(repoArg)
+		if err != nil {
+			return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+		}
+		queryParts = append(queryParts, fmt.Sprintf("repo:%s/%s", owner, repo))
+	}
+
+	if language := req.GetString("language", ""); language != "" {
+		queryParts = append(queryParts, fmt.Sprintf("language:%s", language))
+	}
+
+	fullQuery := strings.Join(queryParts, " ")
+	if !hasSearchScope(fullQuery) {
+		return toolError("Code search must be scoped: set repo, or include an org:, user:, or repo: qualifier in the query"), nil
+	}
+
+	limit := getLimit(req, 20)
+	opts := &github.SearchOptions{
+		ListOptions: github.ListOptions{PerPage: limit},
+	}
+
+	var hits []*github.CodeResult
+	total := 0
+	for {
+		result, resp, err := s.restClient.Search.Code(ctx, fullQuery, opts)
+		if err != nil {
+			return apiError("Error searching code", err)
+		}
+
+		total = result.GetTotal()
+		hits = append(hits, result.CodeResults...)
+
+		if len(hits) >= limit || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	if total == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No code found matching: %s", fullQuery)), nil
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %d code results for %q, showing %d:\n\n", total, fullQuery, len(hits)))
+	for _, hit := range hits {
+		responseBuilder.WriteString(fmt.Sprintf("- %s: %s\n  %s\n",
+			hit.GetRepository().GetFullName(),
+			hit.GetPath(),
+			hit.GetHTMLURL(),
+		))
+	}
+
+	responseBuilder.WriteString(paginationFooter(len(hits), total))
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func (s *githubService) searchIssuesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := req.RequireString("query")
+	if err != nil || strings.TrimSpace(query) == "" {
+		return toolError("Missing required argument: query"), nil
+	}
+
+	limit := getLimit(req, 20)
+	opts := &github.SearchOptions{
+		Sort:        req.GetString("sort", ""),
+		Order:       req.GetString("order", ""),
+		ListOptions: github.ListOptions{PerPage: limit},
+	}
+
+	var issues []*github.Issue
+	total := 0
+	for {
+		result, resp, err := s.restClient.Search.Issues(ctx, query, opts)
+		if err != nil {
+			return apiError("Error searching GitHub", err)
+		}
+
+		total = result.GetTotal()
+		issues = append(issues, result.Issues...)
+
+		if len(issues) >= limit || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(issues) > limit {
+		issues = issues[:limit]
+	}
+
+	if total == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No issues or pull requests found matching: %s", query)), nil
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %d results for %q, showing %d:\n\n", total, query, len(issues)))
+	for _, issue := range issues {
+		responseBuilder.WriteString(fmt.Sprintf("- [%s, State: %s] %s\n  %s\n",
+			issueType(issue),
+			issue.GetState(),
+			issue.GetTitle(),
+			issue.GetHTMLURL(),
+		))
+	}
+
+	responseBuilder.WriteString(paginationFooter(len(issues), total))
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func issueType(issue *github.Issue) string {
+	if issue.IsPullRequest() {
+		return "PR"
+	}
+	return "Issue"
+}
+
+// issueRepoName returns the "owner/name" of the repository an issue search
+// result belongs to, derived from its API repository URL.
+func issueRepoName(issue *github.Issue) string {
+	parts := strings.Split(strings.TrimSuffix(issue.GetRepositoryURL(), "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[len(parts)-2] + "/" + parts[len(parts)-1]
+}
+
+// searchRecentIssues runs an issue search, most recently updated first, and
+// collects up to limit results along with the total match count.
+func (s *githubService) searchRecentIssues(ctx context.Context, query string, limit int) ([]*github.Issue, int, error) {
+	return s.searchIssuesByUpdate(ctx, query, "desc", limit)
+}
+
+// searchIssuesByUpdate runs an issue search ordered by last update, in the
+// given order (asc or desc), and collects up to limit results along with the
+// total match count.
+func (s *githubService) searchIssuesByUpdate(ctx context.Context, query, order string, limit int) ([]*github.Issue, int, error) {
+	opts := &github.SearchOptions{
+		Sort:        "updated",
+		Order:       order,
+		ListOptions: github.ListOptions{PerPage: limit},
+	}
+
+	var issues []*github.Issue
+	total := 0
+	for {
+		result, resp, err := s.restClient.Search.Issues(ctx, query, opts)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		total = result.GetTotal()
+		issues = append(issues, result.Issues...)
+
+		if len(issues) >= limit || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(issues) > limit {
+		issues = issues[:limit]
+	}
+
+	return issues, total, nil
+}
+
+func (s *githubService) listMyReviewRequestsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	queryParts := []string{"is:pr", "is:open", fmt.Sprintf("review-requested:%s", s.viewer())}
+
+	if repoArg := req.GetString("repo", ""); repoArg != "" {
+		owner, repo, err := parseRepo(repoArg)
+		if err != nil {
+			return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+		}
+		queryParts = append(queryParts, fmt.Sprintf("repo:%s/%s", owner, repo))
+	}
+
+	if excluded := req.GetString("exclude_author", ""); excluded != "" {
+		queryParts = append(queryParts, fmt.Sprintf("-author:%s", excluded))
+	}
+
+	// Team review requests can include pull requests the user opened.
+	if req.GetBool("exclude_own", false) {
+		queryParts = append(queryParts, fmt.Sprintf("-author:%s", s.viewer()))
+	}
+
+	issues, total, err := s.searchRecentIssues(ctx, strings.Join(queryParts, " "), getLimit(req, 30))
+	if err != nil {
+		return apiError("Error searching GitHub", err)
+	}
+
+	if total == 0 {
+		return mcp.NewToolResultText("No pull requests are waiting on your review."), nil
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %d pull requests awaiting your review, showing %d:\n\n", total, len(issues)))
+	for _, issue := range issues {
+		responseBuilder.WriteString(fmt.Sprintf("- [%s] %s by @%s\n  %s\n",
+			issueRepoName(issue),
+			issue.GetTitle(),
+			issue.GetUser().GetLogin(),
+			issue.GetHTMLURL(),
+		))
+	}
+
+	responseBuilder.WriteString(paginationFooter(len(issues), total))
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func (s *githubService) listOrgOpenPRsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	org, err := req.RequireString("org")
+	if err != nil {
+		return toolError("Missing required argument: org"), nil
+	}
+
+	org = strings.TrimSpace(org)
+	if len(org) > 39 || !orgLoginRegex.MatchString(org) {
+		return toolError(fmt.Sprintf("Invalid org %q: expected an organization login such as 'my-org'", org)), nil
+	}
+
+	queryParts := []string{"is:pr", "is:open", fmt.Sprintf("org:%s", org)}
+	if author := strings.TrimPrefix(strings.TrimSpace(req.GetString("author", "")), "@"); author != "" {
+		queryParts = append(queryParts, fmt.Sprintf("author:%s", author))
+	}
+	if label := strings.TrimSpace(req.GetString("label", "")); label != "" {
+		queryParts = append(queryParts, fmt.Sprintf("label:%q", label))
+	}
+
+	// Oldest update first, so the pull requests waiting longest lead the queue.
+	issues, total, err := s.searchIssuesByUpdate(ctx, strings.Join(queryParts, " "), "asc", getLimit(req, 30))
+	if err != nil {
+		return apiError("Error searching GitHub", err)
+	}
+
+	if total == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No open pull requests found in %s.", org)), nil
+	}
+
+	now := time.Now()
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %d open pull requests in %s, least recently updated first, showing %d:\n\n", total, org, len(issues)))
+	responseBuilder.WriteString("| Updated | Repository | PR | Author | Title |\n")
+	responseBuilder.WriteString("|---|---|---|---|---|\n")
+	for _, issue := range issues {
+		draft := ""
+		if issue.GetDraft() {
+			draft = " (draft)"
+		}
+		responseBuilder.WriteString(fmt.Sprintf("| %s | %s | [#%d](%s) | @%s | %s%s |\n",
+			humanizeSince(issue.GetUpdatedAt().Time, now),
+			issueRepoName(issue),
+			issue.GetNumber(),
+			issue.GetHTMLURL(),
+			issue.GetUser().GetLogin(),
+			strings.ReplaceAll(issue.GetTitle(), "|", "\\|"),
+			draft,
+		))
+	}
+
+	responseBuilder.WriteString(paginationFooter(len(issues), total))
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+func (s *githubService) getMyMentionsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	queryParts := []string{"is:open", fmt.Sprintf("mentions:%s", s.viewer())}
+
+	if repoArg := req.GetString("repo", ""); repoArg != "" {
+		owner, repo, err := parseRepo(repoArg)
+		if err != nil {
+			return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+		}
+		queryParts = append(queryParts, fmt.Sprintf("repo:%s/%s", owner, repo))
+	}
+
+	issues, total, err := s.searchRecentIssues(ctx, strings.Join(queryParts, " "), getLimit(req, 30))
+	if err != nil {
+		return apiError("Error searching GitHub", err)
+	}
+
+	if total == 0 {
+		return mcp.NewToolResultText("No open issues or pull requests mention you."), nil
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %d open issues and pull requests mentioning you, showing %d:\n\n", total, len(issues)))
+	for _, issue := range issues {
+		responseBuilder.WriteString(fmt.Sprintf("- [%s, %s] %s by @%s\n  %s\n",
+			issueType(issue),
+			issueRepoName(issue),
+			issue.GetTitle(),
+			issue.GetUser().GetLogin(),
+			issue.GetHTMLURL(),
+		))
+	}
+
+	responseBuilder.WriteString(paginationFooter(len(issues), total))
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
+
+// myComment is a comment by the authenticated user found by
+// search_my_comments.
+type myComment struct {
+	issue     *github.Issue
+	body      string
+	url       string
+	createdAt time.Time
+}
+
+// commentSnippet returns the part of body around the first case-insensitive
+// match of term, with about radius bytes of context on either side.
+func commentSnippet(body, term string, radius int) string {
+	index := strings.Index(strings.ToLower(body), strings.ToLower(term))
+	if index < 0 {
+		index = 0
+	}
+
+	start := max(0, index-radius)
+	end := min(len(body), index+len(term)+radius)
+	for start > 0 && !utf8.RuneStart(body[start]) {
+		start--
+	}
+	for end < len(body) && !utf8.RuneStart(body[end]) {
+		end++
+	}
+
+	snippet := strings.Join(strings.Fields(body[start:end]), " ")
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(body) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// matchingCommentsBy collects the comments login left on issue that contain term:
+// conversation comments, and review comments when the issue is a pull request.
+func (s *githubService) matchingCommentsBy(ctx context.Context, issue *github.Issue, login, term string) ([]myComment, error) {
+	owner, repo, err := parseRepo(issueRepoName(issue))
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []myComment
+	isMatch := func(author, body string) bool {
+		return strings.EqualFold(author, login) && strings.Contains(strings.ToLower(body), strings.ToLower(term))
+	}
+
+	issueOpts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := s.restClient.Issues.ListComments(ctx, owner, repo, issue.GetNumber(), issueOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			if isMatch(comment.GetUser().GetLogin(), comment.GetBody()) {
+				matches = append(matches, myComment{issue: issue, body: comment.GetBody(), url: comment.GetHTMLURL(), createdAt: comment.GetCreatedAt().Time})
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		issueOpts.Page = resp.NextPage
+	}
+
+	if !issue.IsPullRequest() {
+		return matches, nil
+	}
+
+	reviewOpts := &github.PullRequestListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := s.restClient.PullRequests.ListComments(ctx, owner, repo, issue.GetNumber(), reviewOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			if isMatch(comment.GetUser().GetLogin(), comment.GetBody()) {
+				matches = append(matches, myComment{issue: issue, body: comment.GetBody(), url: comment.GetHTMLURL(), createdAt: comment.GetCreatedAt().Time})
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		reviewOpts.Page = resp.NextPage
+	}
+
+	return matches, nil
+}
+
+func (s *githubService) searchMyCommentsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	term := strings.TrimSpace(req.GetString("query", ""))
+	if term == "" {
+		return toolError("Missing required argument: query"), nil
+	}
+
+	login := s.login
+	if login == "" {
+		user, _, err := s.restClient.Users.Get(ctx, "")
+		if err != nil {
+			return apiError("Failed to get the authenticated user", err)
+		}
+		login = user.GetLogin()
+	}
+
+	queryParts := []string{fmt.Sprintf("%q", term), "in:comments", fmt.Sprintf("commenter:%s", login)}
+	if repoArg := req.GetString("repo", ""); repoArg != "" {
+		owner, repo, err := parseRepo(repoArg)
+		if err != nil {
+			return toolError(fmt.Sprintf("Invalid repo: %v", err)), nil
+		}
+		queryParts = append(queryParts, fmt.Sprintf("repo:%s/%s", owner, repo))
+	}
+
+	limit := getLimit(req, 20)
+	issues, _, err := s.searchRecentIssues(ctx, strings.Join(queryParts, " "), limit)
+	if err != nil {
+		return apiError("Error searching GitHub", err)
+	}
+
+	// Search only finds the issues; the comments themselves are fetched per
+	// issue and filtered here.
+	perIssue := make([][]myComment, len(issues))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrentPRFetches)
+	for i, issue := range issues {
+		group.Go(func() error {
+			matches, err := s.matchingCommentsBy(groupCtx, issue, login, term)
+			perIssue[i] = matches
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return apiError("Failed to list comments", err)
+	}
+
+	var matches []myComment
+	for _, issueMatches := range perIssue {
+		matches = append(matches, issueMatches...)
+	}
+
+	if len(matches) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No comments by @%s containing %q found.", login, term)), nil
+	}
+
+	shown := matches
+	if len(shown) > limit {
+		shown = shown[:limit]
+	}
+
+	var responseBuilder strings.Builder
+	responseBuilder.WriteString(fmt.Sprintf("Found %d comments by @%s containing %q, showing %d:\n\n", len(matches), login, term, len(shown)))
+	for _, match := range shown {
+		responseBuilder.WriteString(fmt.Sprintf("- [%s] %s (%s)\n  %s\n  %s\n",
+			issueRepoName(match.issue),
+			match.issue.GetTitle(),
+			formatTimestamp(match.createdAt, false),
+			commentSnippet(match.body, term, 80),
+			match.url,
+		))
+	}
+
+	responseBuilder.WriteString(paginationFooter(len(shown), len(matches)))
+	return mcp.NewToolResultText(responseBuilder.String()), nil
+}
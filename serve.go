@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultListenAddr is where the sse and http transports listen unless
+// MCP_LISTEN_ADDR says otherwise.
+const defaultListenAddr = "localhost:8080"
+
+// streamableHTTPPath is the endpoint of the streamable HTTP transport.
+const streamableHTTPPath = "/mcp"
+
+// shutdownTimeout bounds how long in-flight HTTP requests may take to finish
+// once shutdown begins.
+const shutdownTimeout = 10 * time.Second
+
+// networkServer is implemented by both the SSE and streamable HTTP servers.
+type networkServer interface {
+	Start(addr string) error
+	Shutdown(ctx context.Context) error
+}
+
+// serve runs s on the transport named by MCP_TRANSPORT until ctx is
+// cancelled: "stdio" (the default) for clients that launch the server as a
+// subprocess, or "sse" and "http" (streamable HTTP) for a long-lived server
+// that clients connect to over the network. A cancelled ctx is a clean
+// shutdown and returns nil.
+func serve(ctx context.Context, s *server.MCPServer) error {
+	transport := strings.ToLower(strings.TrimSpace(os.Getenv("MCP_TRANSPORT")))
+	if transport == "" {
+		transport = "stdio"
+	}
+
+	addr := os.Getenv("MCP_LISTEN_ADDR")
+	if addr == "" {
+		addr = defaultListenAddr
+	}
+
+	switch transport {
+	case "stdio":
+		slog.Info("MCP server running. Waiting for requests from Claude CLI...", "transport", transport)
+		err := server.NewStdioServer(s).Listen(ctx, os.Stdin, os.Stdout)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return err
+		}
+		return nil
+	// The http.Server is created up front rather than by Start, so that a
+	// shutdown arriving before Start has run still finds a server to stop.
+	case "sse":
+		slog.Info("MCP server listening", "transport", transport, "addr", addr)
+		httpServer := &http.Server{Addr: addr}
+		sseServer := server.NewSSEServer(s, server.WithHTTPServer(httpServer))
+		httpServer.Handler = sseServer
+		return serveNetwork(ctx, sseServer, addr)
+	case "http":
+		slog.Info("MCP server listening", "transport", transport, "addr", addr)
+		httpServer := &http.Server{Addr: addr}
+		streamableServer := server.NewStreamableHTTPServer(s,
+			server.WithEndpointPath(streamableHTTPPath),
+			server.WithStreamableHTTPServer(httpServer),
+		)
+		mux := http.NewServeMux()
+		mux.Handle(streamableHTTPPath, streamableServer)
+		httpServer.Handler = mux
+		return serveNetwork(ctx, streamableServer, addr)
+	default:
+		return fmt.Errorf("invalid MCP_TRANSPORT %q: must be stdio, sse or http", transport)
+	}
+}
+
+// serveNetwork starts srv on addr and shuts it down gracefully when ctx is
+// cancelled, letting in-flight requests finish within shutdownTimeout.
+func serveNetwork(ctx context.Context, srv networkServer, addr string) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Start(addr)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown failed: %v", err)
+	}
+	if err := <-errs; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// serveWithin runs serve and fails the test if it has not returned within a
+// few seconds, which would mean shutdown hung.
+func serveWithin(t *testing.T, ctx context.Context) error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, server.NewMCPServer("test", "0.0.0"))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not return after its context was cancelled")
+		return nil
+	}
+}
+
+func TestServeShutsDownNetworkTransports(t *testing.T) {
+	for _, transport := range []string{"sse", "http"} {
+		t.Run(transport, func(t *testing.T) {
+			t.Setenv("MCP_TRANSPORT", transport)
+			t.Setenv("MCP_LISTEN_ADDR", "127.0.0.1:0")
+
+			t.Run("running", func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+				if err := serveWithin(t, ctx); err != nil {
+					t.Errorf("serve: %v", err)
+				}
+			})
+
+			// Cancelling before the listener has started must not hang or
+			// panic.
+			t.Run("cancelled before start", func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				if err := serveWithin(t, ctx); err != nil {
+					t.Errorf("serve: %v", err)
+				}
+			})
+		})
+	}
+}
+
+func TestServeRejectsUnknownTransport(t *testing.T) {
+	t.Setenv("MCP_TRANSPORT", "carrier-pigeon")
+	if err := serve(context.Background(), server.NewMCPServer("test", "0.0.0")); err == nil {
+		t.Error("serve accepted an unknown transport")
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Handlers report failures in one of two ways:
+//
+//   - A tool error result, via toolError, for anything the caller can act on:
+//     invalid arguments, missing resources, permission problems and
+//     validation failures from GitHub. The model sees these as the tool's
+//     output and can correct its next call.
+//   - A non-nil Go error, via apiError, for infrastructure failures the caller
+//     cannot fix by changing arguments: expired or revoked credentials,
+//     network failures and timeouts, rate limiting, and GitHub server errors.
+//     The MCP client receives these as protocol errors.
+//
+// Empty results are neither; they are plain text results.
+
+// apiError reports a failed GitHub API call according to that policy,
+// prefixing the GitHub error with message.
+func apiError(message string, err error) (*mcp.CallToolResult, error) {
+	text := fmt.Sprintf("%s: %s", message, githubErrorMessage(err))
+	if isInfrastructureError(err) {
+		return nil, errors.New(redactSecrets(text))
+	}
+	return toolError(text), nil
+}
+
+// isInfrastructureError reports whether err comes from the environment rather
+// than from the request: authentication, transport, rate limiting or a
+// GitHub-side failure.
+func isInfrastructureError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return true
+	}
+
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr) {
+		return true
+	}
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) {
+		if errResp.Response == nil {
+			return false
+		}
+		status := errResp.Response.StatusCode
+		return status == http.StatusUnauthorized || status >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// The GraphQL client reports HTTP failures as plain errors.
+	message := err.Error()
+	return strings.Contains(message, "non-200 OK status code: 401") || strings.Contains(message, "non-200 OK status code: 5")
+}
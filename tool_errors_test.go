@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+)
+
+func githubStatusError(status int, message string) error {
+	return &github.ErrorResponse{
+		Response: &http.Response{StatusCode: status, Request: &http.Request{Method: http.MethodGet}},
+		Message:  message,
+	}
+}
+
+func TestAPIErrorPaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantGoErr bool
+	}{
+		{name: "not found", err: githubStatusError(http.StatusNotFound, "Not Found")},
+		{name: "validation failed", err: githubStatusError(http.StatusUnprocessableEntity, "Validation Failed")},
+		{name: "forbidden", err: githubStatusError(http.StatusForbidden, "Resource not accessible")},
+		{name: "unauthorized", err: githubStatusError(http.StatusUnauthorized, "Bad credentials"), wantGoErr: true},
+		{name: "server error", err: githubStatusError(http.StatusBadGateway, "Bad Gateway"), wantGoErr: true},
+		{name: "wrapped server error", err: fmt.Errorf("failed to list check runs: %w", githubStatusError(http.StatusInternalServerError, "Server Error")), wantGoErr: true},
+		{name: "wrapped not found", err: fmt.Errorf("failed to list labels: %w", githubStatusError(http.StatusNotFound, "Not Found"))},
+		{name: "rate limited", err: &github.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden, Request: &http.Request{Method: http.MethodGet}}, Message: "API rate limit exceeded"}, wantGoErr: true},
+		{name: "wrapped context canceled", err: fmt.Errorf("GitHub GraphQL query failed: %w", context.Canceled), wantGoErr: true},
+		{name: "graphql server error", err: errors.New("non-200 OK status code: 502 Bad Gateway body: \"\""), wantGoErr: true},
+		{name: "graphql not found", err: errors.New("Could not resolve to a PullRequest with the number of 9.")},
+		{name: "plain validation error", err: errors.New("unknown labels in owner/repo: nope")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := apiError("Failed", tt.err)
+			if tt.wantGoErr {
+				if err == nil {
+					t.Fatalf("got a tool result, want a Go error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got Go error %v, want a tool error", err)
+			}
+			if result == nil || !result.IsError {
+				t.Fatalf("result = %+v, want a tool error", result)
+			}
+		})
+	}
+}
+
+func TestGithubErrorMessageKeepsWrappingContext(t *testing.T) {
+	err := fmt.Errorf("failed to list check runs: %w", &github.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusNotFound, Request: &http.Request{Method: http.MethodGet}},
+		Message:  "Not Found",
+	})
+	if got, want := githubErrorMessage(err), "failed to list check runs: Not Found"; got != want {
+		t.Errorf("githubErrorMessage = %q, want %q", got, want)
+	}
+}
+
+// statusHandler serves a pull request whose check runs endpoint fails with
+// checksStatus.
+func statusHandler(checksStatus int) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"number":1,"head":{"sha":"abc123"}}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/commits/abc123/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(checksStatus)
+		io.WriteString(w, `{"message":"checks unavailable"}`)
+	})
+	return mux
+}
+
+func TestGetPRStatusErrorPaths(t *testing.T) {
+	req := toolRequest(map[string]interface{}{"pull_request_url": "https://github.com/owner/repo/pull/1"})
+
+	t.Run("server error is a Go error", func(t *testing.T) {
+		s := withREST(t, newTestService(nil), statusHandler(http.StatusInternalServerError))
+		result, err := s.getPRStatusHandler(context.Background(), req)
+		if err == nil {
+			t.Fatalf("got result %+v, want a Go error", result)
+		}
+		if !strings.Contains(err.Error(), "failed to list check runs") {
+			t.Errorf("error %q lost the fetchChecks context", err)
+		}
+	})
+
+	t.Run("not found is a tool error", func(t *testing.T) {
+		s := withREST(t, newTestService(nil), statusHandler(http.StatusNotFound))
+		result, err := s.getPRStatusHandler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("got Go error %v, want a tool error", err)
+		}
+		if !result.IsError {
+			t.Fatalf("result is not an error: %+v", result)
+		}
+	})
+}
+
+func TestMergeErrorPaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantGoErr bool
+		wantText  string
+	}{
+		{name: "conflict keeps its message", status: http.StatusConflict, wantText: "head branch was modified"},
+		{name: "status checks keep their message", status: http.StatusMethodNotAllowed, wantText: "required status checks"},
+		{name: "validation is a tool error", status: http.StatusUnprocessableEntity, wantText: "Failed to merge pull request"},
+		{name: "server error is a Go error", status: http.StatusBadGateway, wantGoErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/repos/owner/repo/pulls/1/merge", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, `{"message":"Required status check \"build\" is failing"}`)
+			})
+			s := withREST(t, newTestService(nil), mux)
+
+			result, err := s.mergePullRequestHandler(context.Background(), toolRequest(map[string]interface{}{
+				"pull_request_url": "https://github.com/owner/repo/pull/1",
+			}))
+			if tt.wantGoErr {
+				if err == nil {
+					t.Fatalf("got result %+v, want a Go error", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got Go error %v, want a tool error", err)
+			}
+			if !result.IsError || !strings.Contains(resultText(t, result), tt.wantText) {
+				t.Errorf("result = %q, want a tool error containing %q", resultText(t, result), tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retryTransport retries requests that GitHub rejected because of secondary
+// rate limits. A Retry-After header is honoured when present; otherwise the
+// delay doubles on every attempt, starting at baseDelay.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+
+	// after is time.After, replaceable so tests can observe the delays
+	// without waiting for them.
+	after func(time.Duration) <-chan time.Time
+}
+
+func newRetryTransport(base http.RoundTripper, maxRetries int) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &retryTransport{
+		base:       base,
+		maxRetries: maxRetries,
+		baseDelay:  time.Second,
+		after:      time.After,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || attempt >= t.maxRetries || !isRetryable(resp) {
+			return resp, err
+		}
+
+		// A request with a body can only be replayed if it can be rewound.
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		delay := retryDelay(resp, t.baseDelay<<attempt)
+		resp.Body.Close()
+
+		slog.Warn("GitHub rate limited the request, retrying",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"status", resp.StatusCode,
+			"delay", delay,
+			"attempt", attempt+1,
+			"max_retries", t.maxRetries,
+		)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-t.after(delay):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// maxRateLimitBodyBytes bounds how much of a 403 body is read to look for a
+// secondary rate limit message.
+const maxRateLimitBodyBytes = 64 * 1024
+
+// isRetryable reports whether resp signals a secondary rate limit: a 429, a
+// 403 that tells the client when to come back, or a 403 whose message says a
+// secondary (abuse) rate limit was hit, which GitHub sometimes sends without
+// Retry-After.
+func isRetryable(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("Retry-After") != "" || isSecondaryRateLimitBody(resp)
+	}
+	return false
+}
+
+// isSecondaryRateLimitBody peeks at the start of resp's body for GitHub's
+// secondary rate limit message. The body is restored so the response can
+// still be returned to the caller unchanged.
+func isSecondaryRateLimitBody(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+
+	prefix, err := io.ReadAll(io.LimitReader(resp.Body, maxRateLimitBodyBytes))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+
+	message := strings.ToLower(string(prefix))
+	return strings.Contains(message, "secondary rate limit") || strings.Contains(message, "abuse")
+}
+
+func retryDelay(resp *http.Response, fallback time.Duration) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return fallback
+}
+
+// loggingTransport emits a debug log line for every request sent to GitHub.
+type loggingTransport struct {
+	base http.RoundTripper
+}
+
+func newLoggingTransport(base http.RoundTripper) *loggingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &loggingTransport{base: base}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		slog.Debug("GitHub API request failed",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"duration", duration,
+			"error", redactSecrets(err.Error()),
+		)
+		return resp, err
+	}
+
+	slog.Debug("GitHub API request",
+		"method", req.Method,
+		"path", req.URL.Path,
+		"status", resp.StatusCode,
+		"duration", duration,
+	)
+	return resp, nil
+}
@@ -1,27 +1,260 @@
 package main
 
-import "github.com/shurcooL/githubv4"
+import (
+	"fmt"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+type pageInfo struct {
+	HasNextPage githubv4.Boolean
+	EndCursor   githubv4.String
+}
+
+type reviewComment struct {
+	DatabaseID githubv4.Int
+	Author     struct {
+		Login githubv4.String
+	}
+	Body githubv4.String
+	// DiffHunk can be large and most tools never show it, so it is only
+	// selected when the query's $withHunk variable is true.
+	DiffHunk     githubv4.String `graphql:"diffHunk @include(if: $withHunk)"`
+	Path         githubv4.String
+	Line         githubv4.Int
+	OriginalLine githubv4.Int
+	URL          githubv4.URI
+	CreatedAt    githubv4.DateTime
+
+	ReactionGroups []struct {
+		Content githubv4.ReactionContent
+		Users   struct {
+			TotalCount githubv4.Int
+		}
+	}
+}
+
+type reviewThread struct {
+	ID         githubv4.ID
+	IsResolved githubv4.Boolean
+	IsOutdated githubv4.Boolean
+	ResolvedBy struct {
+		Login githubv4.String
+	}
+	Comments struct {
+		Nodes    []reviewComment
+		PageInfo pageInfo
+	} `graphql:"comments(first: 20)"`
+}
+
+// queryRateLimit selects the rate limit cost of the query it is part of.
+type queryRateLimit struct {
+	Cost      githubv4.Int
+	Remaining githubv4.Int
+}
 
 type prCommentsQuery struct {
+	RateLimit  queryRateLimit
 	Repository struct {
 		PullRequest struct {
 			ReviewThreads struct {
+				Nodes    []reviewThread
+				PageInfo pageInfo
+			} `graphql:"reviewThreads(first: 100, after: $after)"`
+		} `graphql:"pullRequest(number: $prNumber)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type threadCommentsQuery struct {
+	RateLimit queryRateLimit
+	Node      struct {
+		PullRequestReviewThread struct {
+			Comments struct {
+				Nodes    []reviewComment
+				PageInfo pageInfo
+			} `graphql:"comments(first: 100, after: $after)"`
+		} `graphql:"... on PullRequestReviewThread"`
+	} `graphql:"node(id: $threadId)"`
+}
+
+type reviewThreadStatusQuery struct {
+	Node struct {
+		PullRequestReviewThread struct {
+			IsResolved githubv4.Boolean
+			Comments   struct {
 				Nodes []struct {
-					IsResolved githubv4.Boolean
-					Comments   struct {
-						Nodes []struct {
-							Author struct {
-								Login githubv4.String
-							}
-							Body      githubv4.String
-							Path      githubv4.String
-							Line      githubv4.Int
-							URL       githubv4.URI
-							CreatedAt githubv4.DateTime
-						}
-					} `graphql:"comments(first: 20)"`
+					DatabaseID githubv4.Int
+					Path       githubv4.String
+				}
+			} `graphql:"comments(first: 1)"`
+			PullRequest struct {
+				Number     githubv4.Int
+				Repository struct {
+					Name  githubv4.String
+					Owner struct {
+						Login githubv4.String
+					}
 				}
-			} `graphql:"reviewThreads(first: 100)"`
+			}
+		} `graphql:"... on PullRequestReviewThread"`
+	} `graphql:"node(id: $threadId)"`
+}
+
+type resolveReviewThreadMutation struct {
+	ResolveReviewThread struct {
+		Thread struct {
+			IsResolved githubv4.Boolean
+		}
+	} `graphql:"resolveReviewThread(input: $input)"`
+}
+
+type pullRequestNodeQuery struct {
+	Repository struct {
+		PullRequest struct {
+			ID      githubv4.ID
+			IsDraft githubv4.Boolean
 		} `graphql:"pullRequest(number: $prNumber)"`
 	} `graphql:"repository(owner: $owner, name: $repo)"`
 }
+
+type convertPullRequestToDraftMutation struct {
+	ConvertPullRequestToDraft struct {
+		PullRequest struct {
+			IsDraft githubv4.Boolean
+		}
+	} `graphql:"convertPullRequestToDraft(input: $input)"`
+}
+
+type markPullRequestReadyForReviewMutation struct {
+	MarkPullRequestReadyForReview struct {
+		PullRequest struct {
+			IsDraft githubv4.Boolean
+		}
+	} `graphql:"markPullRequestReadyForReview(input: $input)"`
+}
+
+type blameRange struct {
+	StartingLine githubv4.Int
+	EndingLine   githubv4.Int
+	Commit       struct {
+		Oid             githubv4.GitObjectID
+		MessageHeadline githubv4.String
+		CommittedDate   githubv4.DateTime
+		URL             githubv4.URI
+		Author          struct {
+			Name githubv4.String
+			User struct {
+				Login githubv4.String
+			}
+		}
+	}
+}
+
+type blameCommit struct {
+	Oid   githubv4.GitObjectID
+	Blame struct {
+		Ranges []blameRange
+	} `graphql:"blame(path: $path)"`
+}
+
+type blameQuery struct {
+	Repository struct {
+		Object struct {
+			Commit blameCommit `graphql:"... on Commit"`
+			// Annotated tags resolve to a Tag object, which is peeled to
+			// the commit it points at.
+			Tag struct {
+				Target struct {
+					Commit blameCommit `graphql:"... on Commit"`
+				}
+			} `graphql:"... on Tag"`
+		} `graphql:"object(expression: $ref)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type viewerQuery struct {
+	Viewer struct {
+		Login githubv4.String
+	}
+}
+
+type rateLimitQuery struct {
+	RateLimit struct {
+		Limit     githubv4.Int
+		Remaining githubv4.Int
+		Cost      githubv4.Int
+		ResetAt   githubv4.DateTime
+	}
+}
+
+// The types below define the JSON output returned by tools when called with
+// format "json", and by get_threads_structured. Field names are part of the
+// tool contract and must stay stable.
+
+type pullRequestListOutput struct {
+	Total        int                  `json:"total"`
+	Returned     int                  `json:"returned"`
+	State        string               `json:"state"`
+	PullRequests []pullRequestSummary `json:"pullRequests"`
+}
+
+type pullRequestSummary struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	URL    string `json:"url"`
+}
+
+type threadListOutput struct {
+	Total     int            `json:"total"`
+	Truncated bool           `json:"truncated"`
+	Threads   []threadOutput `json:"threads"`
+}
+
+type threadOutput struct {
+	ID         string          `json:"id"`
+	IsResolved bool            `json:"isResolved"`
+	IsOutdated bool            `json:"isOutdated"`
+	Path       string          `json:"path"`
+	Line       int             `json:"line"`
+	URL        string          `json:"url"`
+	Comments   []commentOutput `json:"comments"`
+}
+
+type commentOutput struct {
+	ID        int       `json:"id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+	URL       string    `json:"url"`
+}
+
+func newThreadOutput(thread reviewThread) threadOutput {
+	output := threadOutput{
+		ID:         fmt.Sprint(thread.ID),
+		IsResolved: bool(thread.IsResolved),
+		IsOutdated: bool(thread.IsOutdated),
+		Comments:   []commentOutput{},
+	}
+
+	for i, comment := range thread.Comments.Nodes {
+		if i == 0 {
+			output.Path = string(comment.Path)
+			output.Line = int(comment.Line)
+			if output.Line == 0 {
+				output.Line = int(comment.OriginalLine)
+			}
+			output.URL = comment.URL.String()
+		}
+		output.Comments = append(output.Comments, commentOutput{
+			ID:        int(comment.DatabaseID),
+			Author:    string(comment.Author.Login),
+			Body:      string(comment.Body),
+			CreatedAt: comment.CreatedAt.Time,
+			URL:       comment.URL.String(),
+		})
+	}
+
+	return output
+}